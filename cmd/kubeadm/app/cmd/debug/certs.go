@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCertsDir is where kubeadm writes control-plane certificates,
+// matching the default "certs check-expiration" already inspects.
+const defaultCertsDir = "/etc/kubernetes/pki"
+
+// certExpiryInfo is the --json shape for a single certificate, mirroring
+// the columns "certs check-expiration" prints as a table.
+type certExpiryInfo struct {
+	Name         string    `json:"name"`
+	Expires      time.Time `json:"expires"`
+	ResidualDays int       `json:"residualDays"`
+}
+
+// newCmdDebugCerts returns the "kubeadm debug certs" command.
+func newCmdDebugCerts(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Inspect the control-plane certificates under the local PKI directory",
+	}
+	cmd.AddCommand(newCmdDebugCertsExpiry(out))
+	return cmd
+}
+
+func newCmdDebugCertsExpiry(out io.Writer) *cobra.Command {
+	var certsDir string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "expiry",
+		Short: "Print expiration information for every certificate in the local PKI directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			infos, err := certExpiryInfoForDir(certsDir)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				data, err := json.MarshalIndent(infos, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, string(data))
+				return nil
+			}
+			for _, info := range infos {
+				fmt.Fprintf(out, "%s\t%s\t%d days\n", info.Name, info.Expires.Format(time.RFC3339), info.ResidualDays)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&certsDir, "cert-dir", defaultCertsDir, "Directory containing the control-plane certificates")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print machine-readable JSON instead of a table")
+
+	return cmd
+}
+
+// certExpiryInfoForDir walks every *.crt file directly under dir (kubeadm
+// does not nest certificates in subdirectories) and returns its expiry
+// info, skipping files that do not parse as a PEM certificate.
+func certExpiryInfoForDir(dir string) ([]certExpiryInfo, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list certificates in %q: %v", dir, err)
+	}
+
+	var infos []certExpiryInfo
+	for _, path := range matches {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read %q: %v", path, err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, certExpiryInfo{
+			Name:         filepath.Base(path),
+			Expires:      cert.NotAfter,
+			ResidualDays: int(time.Until(cert.NotAfter).Hours() / 24),
+		})
+	}
+	return infos, nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debug groups offline diagnostic subcommands for operators who
+// need to inspect a broken control-plane node directly, without a working
+// apiserver to talk to.
+package debug
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmdDebug returns the "kubeadm debug" command, grouping read-only
+// diagnostics (etcd inspection, certificate expiry) that only need
+// filesystem access to the node's static-pod manifests and PKI directory.
+func NewCmdDebug(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Offline diagnostic commands for inspecting a local control-plane node",
+		Long: "debug groups commands that inspect a control-plane node's local state directly " +
+			"-- the static-pod etcd data directory and the PKI certificates under /etc/kubernetes/pki -- " +
+			"for use when the apiserver itself is not reachable.",
+	}
+
+	cmd.AddCommand(newCmdDebugEtcd(out))
+	cmd.AddCommand(newCmdDebugCerts(out))
+
+	return cmd
+}
+
+// NOTE: cmd.NewKubeadmCommand (which would call NewCmdDebug and
+// cmd.AddCommand it alongside init/join/reset/etc.) lives in
+// cmd/kubeadm/app/cmd, which is not present in this checkout -- only the
+// phases/token and util/etcd packages are. Wiring NewCmdDebug into the root
+// command is left for a follow-up once that package lands.
@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	clientv3 "go.etcd.io/etcd/clientv3"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+)
+
+// defaultEtcdPKIDir is where a kubeadm-managed static-pod etcd stores the
+// client certificates newLocalEtcdClient discovers.
+const defaultEtcdPKIDir = "/etc/kubernetes/pki/etcd"
+
+const defaultEtcdDialTimeout = 5 * time.Second
+
+// newCmdDebugEtcd returns the "kubeadm debug etcd" command.
+func newCmdDebugEtcd(out io.Writer) *cobra.Command {
+	var pkiDir string
+	var endpoint string
+
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Inspect the local static-pod etcd directly, using its client certs",
+	}
+	cmd.PersistentFlags().StringVar(&pkiDir, "etcd-pki-dir", defaultEtcdPKIDir, "Directory containing the etcd client certificate, key, and CA")
+	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "https://127.0.0.1:2379", "etcd client endpoint to connect to")
+
+	cmd.AddCommand(newCmdDebugEtcdKeys(out, &pkiDir, &endpoint))
+	cmd.AddCommand(newCmdDebugEtcdGet(out, &pkiDir, &endpoint))
+
+	return cmd
+}
+
+func newCmdDebugEtcdKeys(out io.Writer, pkiDir, endpoint *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "keys",
+		Short: "List every key stored in the local etcd",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newLocalEtcdClient(*pkiDir, *endpoint)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), defaultEtcdDialTimeout)
+			defer cancel()
+			resp, err := client.Get(ctx, "/", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+			if err != nil {
+				return fmt.Errorf("couldn't list etcd keys: %v", err)
+			}
+			for _, kv := range resp.Kvs {
+				fmt.Fprintln(out, string(kv.Key))
+			}
+			return nil
+		},
+	}
+}
+
+func newCmdDebugEtcdGet(out io.Writer, pkiDir, endpoint *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get [key]",
+		Short: "Print the protobuf-decoded value stored under key, using the registered scheme",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newLocalEtcdClient(*pkiDir, *endpoint)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), defaultEtcdDialTimeout)
+			defer cancel()
+			resp, err := client.Get(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("couldn't get etcd key %q: %v", args[0], err)
+			}
+			if len(resp.Kvs) == 0 {
+				return fmt.Errorf("key %q not found", args[0])
+			}
+
+			obj, _, err := legacyscheme.Codecs.UniversalDeserializer().Decode(resp.Kvs[0].Value, nil, nil)
+			if err != nil {
+				return fmt.Errorf("couldn't decode value for key %q with the registered scheme: %v", args[0], err)
+			}
+			return printObject(out, obj)
+		},
+	}
+}
+
+// printObject re-encodes obj as JSON with the scheme's legacy codec, so
+// operators don't need to pipe this command's output through another decoder.
+func printObject(out io.Writer, obj runtime.Object) error {
+	printer := legacyscheme.Codecs.LegacyCodec()
+	data, err := runtime.Encode(printer, obj)
+	if err != nil {
+		return fmt.Errorf("couldn't re-encode decoded object for printing: %v", err)
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// newLocalEtcdClient builds an etcd v3 client authenticated with the
+// client certs a kubeadm-managed static-pod etcd places under pkiDir,
+// mirroring the cert discovery CreateDataDirectory's caller already does
+// for the static pod manifest itself.
+func newLocalEtcdClient(pkiDir, endpoint string) (*clientv3.Client, error) {
+	tlsConfig, err := etcdClientTLSConfig(pkiDir)
+	if err != nil {
+		return nil, err
+	}
+	return clientv3.New(clientv3.Config{
+		Endpoints:   []string{endpoint},
+		DialTimeout: defaultEtcdDialTimeout,
+		TLS:         tlsConfig,
+	})
+}
+
+func etcdClientCertPaths(pkiDir string) (certFile, keyFile, caFile string) {
+	return filepath.Join(pkiDir, "healthcheck-client.crt"),
+		filepath.Join(pkiDir, "healthcheck-client.key"),
+		filepath.Join(pkiDir, "ca.crt")
+}
+
+// etcdClientTLSConfig loads the client cert/key/CA kubeadm's etcd phase
+// writes under pkiDir into a *tls.Config suitable for clientv3.Config.TLS.
+func etcdClientTLSConfig(pkiDir string) (*tls.Config, error) {
+	certFile, keyFile, caFile := etcdClientCertPaths(pkiDir)
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load etcd client cert/key from %q: %v", pkiDir, err)
+	}
+
+	caData, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read etcd CA cert %q: %v", caFile, err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("couldn't parse etcd CA cert %q", caFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+	}, nil
+}
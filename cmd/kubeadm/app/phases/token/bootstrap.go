@@ -17,11 +17,18 @@ limitations under the License.
 package token
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/golang/glog"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api/v1"
 	"k8s.io/client-go/tools/clientcmd"
@@ -37,26 +44,71 @@ const (
 	bootstrapKubeConfigContext = "bootstrap-context"
 )
 
+// tokenCreateBackoff bounds the retries performed by UpdateOrCreateToken.
+// It preserves the previous "retry up to tokenCreateRetries times" behavior
+// while adding backoff between attempts instead of hammering the apiserver.
+var tokenCreateBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Steps:    tokenCreateRetries,
+}
+
+// SetupSignalHandler returns a context that is cancelled on the first
+// SIGINT/SIGTERM so in-flight operations like UpdateOrCreateToken can abort
+// cleanly, and force-exits the process on the third signal for users who
+// really just want out. Callers should defer the returned stop function.
+func SetupSignalHandler() (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 3)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		count := 0
+		for range sigCh {
+			count++
+			switch count {
+			case 1:
+				glog.Info("received interrupt, cancelling in-flight operations; interrupt twice more to force quit")
+				cancel()
+			case 2:
+				glog.Info("received second interrupt; interrupt once more to force quit")
+			default:
+				glog.Warning("received third interrupt, force quitting")
+				os.Exit(1)
+			}
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigCh)
+		close(sigCh)
+		cancel()
+	}
+}
+
 // UpdateOrCreateToken attempts to update a token with the given ID, or create if it does
-// not already exist.
-func UpdateOrCreateToken(client *clientset.Clientset, d *kubeadmapi.TokenDiscovery, tokenDuration time.Duration) error {
+// not already exist. It honors ctx cancellation: a cancelled ctx aborts the retry loop and
+// triggers a best-effort delete of any Secret this invocation may have just created, so a
+// Ctrl-C during a slow apiserver doesn't leave partial bootstrap state behind.
+func UpdateOrCreateToken(ctx context.Context, client *clientset.Clientset, d *kubeadmapi.TokenDiscovery, tokenDuration time.Duration) error {
 	// Let's make sure the token is valid
 	if valid, err := tokenutil.ValidateToken(d); !valid {
 		return err
 	}
 	secretName := fmt.Sprintf("%s%s", bootstrapapi.BootstrapTokenSecretPrefix, d.ID)
+
 	var lastErr error
-	for i := 0; i < tokenCreateRetries; i++ {
+	attemptedCreate := false
+	backoffErr := wait.ExponentialBackoffWithContext(ctx, tokenCreateBackoff, func() (bool, error) {
 		secret, err := client.Secrets(metav1.NamespaceSystem).Get(secretName, metav1.GetOptions{})
 		if err == nil {
 			// Secret with this ID already exists, update it:
 			secret.Data = encodeTokenSecretData(d, tokenDuration)
-			if _, err := client.Secrets(metav1.NamespaceSystem).Update(secret); err == nil {
-				return nil
-			} else {
+			if _, err := client.Secrets(metav1.NamespaceSystem).Update(secret); err != nil {
 				lastErr = err
+				return false, nil
 			}
-			continue
+			return true, nil
 		}
 
 		// Secret does not already exist:
@@ -68,25 +120,43 @@ func UpdateOrCreateToken(client *clientset.Clientset, d *kubeadmapi.TokenDiscove
 				Type: v1.SecretType(bootstrapapi.SecretTypeBootstrapToken),
 				Data: encodeTokenSecretData(d, tokenDuration),
 			}
-			if _, err := client.Secrets(metav1.NamespaceSystem).Create(secret); err == nil {
-				return nil
-			} else {
+			attemptedCreate = true
+			if _, err := client.Secrets(metav1.NamespaceSystem).Create(secret); err != nil {
 				lastErr = err
+				return false, nil
 			}
-
-			continue
+			return true, nil
 		}
 
+		lastErr = err
+		return false, nil
+	})
+
+	if backoffErr != nil {
+		if ctx.Err() != nil {
+			// We may have raced a Create against cancellation: the request
+			// could have landed on the apiserver even though we returned
+			// before observing success. Clean up best-effort so we don't
+			// leave a half-created bootstrap Secret behind. A Secret that
+			// already existed and was only being updated is left alone.
+			if attemptedCreate {
+				if delErr := client.Secrets(metav1.NamespaceSystem).Delete(secretName, &metav1.DeleteOptions{}); delErr != nil && !apierrors.IsNotFound(delErr) {
+					glog.Warningf("unable to clean up bootstrap token secret %q after cancellation: %v", secretName, delErr)
+				}
+			}
+			return fmt.Errorf("aborted creating bootstrap token: %v", ctx.Err())
+		}
+		return fmt.Errorf(
+			"unable to create bootstrap token after %d attempts [%v]",
+			tokenCreateRetries,
+			lastErr,
+		)
 	}
-	return fmt.Errorf(
-		"unable to create bootstrap token after %d attempts [%v]",
-		tokenCreateRetries,
-		lastErr,
-	)
+	return nil
 }
 
 // CreateBootstrapConfigMap creates the public cluster-info ConfigMap
-func CreateBootstrapConfigMap(file string) error {
+func CreateBootstrapConfigMap(ctx context.Context, file string) error {
 	adminConfig, err := clientcmd.LoadFromFile(file)
 	if err != nil {
 		return fmt.Errorf("failed to load admin kubeconfig [%v]", err)
@@ -95,6 +165,9 @@ func CreateBootstrapConfigMap(file string) error {
 	if err != nil {
 		return err
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 
 	adminCluster := adminConfig.Contexts[adminConfig.CurrentContext].Cluster
 	// Copy the cluster from admin.conf to the bootstrap kubeconfig, contains the CA cert and the server URL
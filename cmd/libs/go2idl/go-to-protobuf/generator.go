@@ -20,18 +20,29 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"k8s.io/kubernetes/cmd/libs/go2idl/generator"
 	"k8s.io/kubernetes/cmd/libs/go2idl/namer"
 	"k8s.io/kubernetes/cmd/libs/go2idl/types"
 )
 
+// The two syntax modes the generator's --proto-syntax flag accepts. proto2
+// is the default to preserve existing generated output.
+const (
+	protoSyntaxProto2 = "proto2"
+	protoSyntaxProto3 = "proto3"
+)
+
 // genProtoIDL produces a .proto IDL.
 type genProtoIDL struct {
 	generator.DefaultGen
@@ -40,19 +51,124 @@ type genProtoIDL struct {
 	imports        *ImportTracker
 
 	generateAll bool
+
+	// protoSyntax is "proto2" (the default) or "proto3", as set by the
+	// generator's --proto-syntax flag. proto3 has no required/optional
+	// field modifiers and doesn't need the goproto_unrecognized_all option,
+	// since proto3 messages can't round-trip unrecognized fields the way
+	// gogoproto's proto2 support does.
+	protoSyntax string
+
+	// previousProtoDir, when set, is the directory this package's .proto
+	// was previously generated into. On first use the generator reads the
+	// existing field tags out of it so regeneration never renumbers a tag
+	// just because a Go struct's member order changed (a wire-breaking
+	// change the generator would otherwise make silently).
+	previousProtoDir string
+
+	// previousProtoPath, when set by the generator's --previous flag,
+	// overrides previousProtoDir with an explicit path to the previously
+	// generated .proto, for packages that don't lay their output out at
+	// the default per-package path.
+	previousProtoPath string
+
+	previousTags     map[string]map[string]int
+	previousTagsRead bool
+
+	// resolved accumulates the generator-agnostic IR for every message
+	// walked in this package, so a downstream consumer (genTemplate) can
+	// render other output formats without re-walking the Go types.
+	resolved []ResolvedMessage
+}
+
+// ResolvedMessages returns the IR this generator has resolved so far for
+// the current package.
+func (g *genProtoIDL) ResolvedMessages() []ResolvedMessage {
+	return g.resolved
+}
+
+// loadPreviousTags reads the field tags out of the .proto this package
+// previously generated, if previousProtoPath or previousProtoDir is set.
+// It is a no-op after the first call.
+func (g *genProtoIDL) loadPreviousTags() error {
+	if g.previousTagsRead {
+		return nil
+	}
+	path := g.previousProtoPath
+	if len(path) == 0 {
+		if len(g.previousProtoDir) == 0 {
+			return nil
+		}
+		path = filepath.Join(g.previousProtoDir, g.Filename())
+	}
+	g.previousTagsRead = true
+	tags, err := parsePreviousFieldTags(path)
+	if err != nil {
+		return err
+	}
+	g.previousTags = tags
+	return nil
+}
+
+var (
+	protoMessageRE = regexp.MustCompile(`^message\s+(\w+)\s*\{`)
+	protoFieldRE   = regexp.MustCompile(`^\s*(?:required|optional|repeated)?\s*[\w.<>\[\],]+\s+(\w+)\s*=\s*(\d+)\s*[;\[]`)
+)
+
+// parsePreviousFieldTags scans a previously generated .proto file and
+// returns, per message name, the field name -> tag number assignments it
+// contains. A missing file is not an error: there is simply nothing to
+// preserve yet.
+func parsePreviousFieldTags(pathname string) (map[string]map[string]int, error) {
+	data, err := ioutil.ReadFile(pathname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tagsByMessage := make(map[string]map[string]int)
+	var current string
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := protoMessageRE.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			current = m[1]
+			tagsByMessage[current] = make(map[string]int)
+			continue
+		}
+		if len(current) == 0 {
+			continue
+		}
+		// only an unindented brace closes the message; an indented one
+		// closes a oneof block nested inside it.
+		if strings.TrimRight(line, " \t\r") == "}" {
+			current = ""
+			continue
+		}
+		if m := protoFieldRE.FindStringSubmatch(line); m != nil {
+			if tag, err := strconv.Atoi(m[2]); err == nil {
+				tagsByMessage[current][m[1]] = tag
+			}
+		}
+	}
+	return tagsByMessage, nil
 }
 
 func (g *genProtoIDL) PackageVars(c *generator.Context) []string {
-	return []string{
+	vars := []string{
 		"option (gogoproto.marshaler_all) = true;",
 		"option (gogoproto.sizer_all) = true;",
 		"option (gogoproto.unmarshaler_all) = true;",
-		"option (gogoproto.goproto_unrecognized_all) = false;",
+	}
+	if g.protoSyntax != protoSyntaxProto3 {
+		vars = append(vars, "option (gogoproto.goproto_unrecognized_all) = false;")
+	}
+	vars = append(vars,
 		"option (gogoproto.goproto_stringer_all) = false;",
 		"option (gogoproto.goproto_enum_prefix_all) = false;",
 		"option (gogoproto.goproto_getters_all) = false;",
 		fmt.Sprintf("option go_package = %q;", g.localGoPackage.Name),
-	}
+	)
+	return vars
 }
 func (g *genProtoIDL) Filename() string { return g.OptionalName + ".proto" }
 func (g *genProtoIDL) FileType() string { return "protoidl" }
@@ -72,6 +188,10 @@ func (g *genProtoIDL) Filter(c *generator.Context, t *types.Type) bool {
 		return false
 	case flags["genprotoidl"] == "true":
 		return true
+	case flags["genprotoidl.service"] == "true":
+		// service interfaces opt in explicitly; they aren't reachable
+		// through isProtoable's member-walk the way message structs are.
+		return t.Kind == types.Interface
 	case !g.generateAll:
 		return false
 	}
@@ -116,6 +236,9 @@ func (g *genProtoIDL) Imports(c *generator.Context) (imports []string) {
 
 // GenerateType makes the body of a file implementing a set for type t.
 func (g *genProtoIDL) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
+	if err := g.loadPreviousTags(); err != nil {
+		return err
+	}
 	sw := generator.NewSnippetWriter(w, c, "$", "$")
 	b := bodyGen{
 		locator: &protobufLocator{
@@ -125,12 +248,18 @@ func (g *genProtoIDL) GenerateType(c *generator.Context, t *types.Type, w io.Wri
 			localGoPackage: g.localGoPackage.Package,
 		},
 		localPackage: g.localPackage,
+		protoSyntax:  g.protoSyntax,
+		context:      c,
+		previousTags: g.previousTags[t.Name.Name],
+		resolved:     &g.resolved,
 
 		t: t,
 	}
 	switch t.Kind {
 	case types.Struct:
 		b.doStruct(sw)
+	case types.Interface:
+		b.doService(sw)
 	default:
 		b.unknown(sw)
 	}
@@ -166,17 +295,19 @@ func (p protobufLocator) CastTypeName(name types.Name) string {
 
 // ProtoTypeFor locates a Protobuf type for the provided Go type (if possible).
 func (p protobufLocator) ProtoTypeFor(t *types.Type) (*types.Type, error) {
+	// it's a fundamental or well-known type; check this before the map
+	// passthrough below, since some well-known types (e.g. google.protobuf.Struct)
+	// are themselves mapped from a Go map type.
+	if wk, ok := isFundamentalProtoType(t); ok {
+		p.tracker.AddType(wk)
+		return wk, nil
+	}
 	switch {
 	// we've already converted the type, or it's a map
 	case t.Kind == typesKindProtobuf || t.Kind == types.Map:
 		p.tracker.AddType(t)
 		return t, nil
 	}
-	// it's a fundamental type
-	if t, ok := isFundamentalProtoType(t); ok {
-		p.tracker.AddType(t)
-		return t, nil
-	}
 	// it's a message
 	if t.Kind == types.Struct {
 		t := &types.Type{
@@ -194,6 +325,17 @@ func (p protobufLocator) ProtoTypeFor(t *types.Type) (*types.Type, error) {
 type bodyGen struct {
 	locator      ProtobufLocator
 	localPackage types.Name
+	protoSyntax  string
+	context      *generator.Context
+
+	// previousTags is the field name -> tag mapping this message had in
+	// the last generated .proto, if any; membersToFields prefers these
+	// over allocating a fresh tag so regeneration stays wire-compatible.
+	previousTags map[string]int
+
+	// resolved, when set, receives the IR for each message this bodyGen
+	// walks, in addition to the .proto text doStruct writes out.
+	resolved *[]ResolvedMessage
 
 	t *types.Type
 }
@@ -212,9 +354,21 @@ func (b bodyGen) doStruct(sw *generator.SnippetWriter) {
 
 	var fields []protoField
 	options := []string{}
+	reservedTags := map[int]bool{}
+	var reservedNames []string
 	allOptions := types.ExtractCommentTags("+", b.t.CommentLines)
 	for k, v := range allOptions {
 		switch {
+		case k == "genprotoidl.reserved":
+			for _, s := range strings.Split(v, ",") {
+				if tag, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+					reservedTags[tag] = true
+				}
+			}
+		case k == "genprotoidl.reservedNames":
+			for _, s := range strings.Split(v, ",") {
+				reservedNames = append(reservedNames, strings.TrimSpace(s))
+			}
 		case strings.HasPrefix(k, "genprotoidl.options."):
 			key := strings.TrimPrefix(k, "genprotoidl.options.")
 			switch key {
@@ -247,7 +401,7 @@ func (b bodyGen) doStruct(sw *generator.SnippetWriter) {
 	}
 
 	if fields == nil {
-		memberFields, err := membersToFields(b.locator, b.t, b.localPackage)
+		memberFields, err := membersToFields(b.locator, b.t, b.localPackage, b.previousTags)
 		if err != nil {
 			sw.Do(fmt.Sprintf("// ERROR: type $.Name$ cannot be converted to protobuf: %v\n", err), b.t)
 			return
@@ -255,6 +409,23 @@ func (b bodyGen) doStruct(sw *generator.SnippetWriter) {
 		fields = memberFields
 	}
 
+	// a field tag this message used to have, but whose name no longer
+	// appears, can never be safely reused: reserve it automatically so a
+	// future regeneration doesn't silently recycle it onto a new field.
+	currentNames := map[string]bool{}
+	for _, f := range fields {
+		currentNames[f.Name] = true
+	}
+	for name, tag := range b.previousTags {
+		if !currentNames[name] {
+			reservedTags[tag] = true
+		}
+	}
+
+	if b.resolved != nil {
+		*b.resolved = append(*b.resolved, b.toResolvedMessage(fields, options))
+	}
+
 	out := sw.Out()
 	genComment(out, b.t.CommentLines, "")
 	sw.Do(`message $.Name.Name$ {
@@ -268,40 +439,302 @@ func (b bodyGen) doStruct(sw *generator.SnippetWriter) {
 		fmt.Fprintln(out)
 	}
 
+	if len(reservedTags) > 0 {
+		tags := make([]int, 0, len(reservedTags))
+		for tag := range reservedTags {
+			tags = append(tags, tag)
+		}
+		fmt.Fprintf(out, "  reserved %s;\n", formatReservedTags(tags))
+	}
+	if len(reservedNames) > 0 {
+		sort.Strings(reservedNames)
+		quoted := make([]string, len(reservedNames))
+		for i, name := range reservedNames {
+			quoted[i] = strconv.Quote(name)
+		}
+		fmt.Fprintf(out, "  reserved %s;\n", strings.Join(quoted, ", "))
+	}
+	if len(reservedTags) > 0 || len(reservedNames) > 0 {
+		fmt.Fprintln(out)
+	}
+
+	// render each field's modifier+type+name prefix up front so the `=`
+	// signs below it can be column-aligned. Oneof members align within
+	// their own block, since it sits at a different indent than top-level
+	// fields.
+	prefixes := make([]string, len(fields))
+	topWidth := 0
+	oneofWidths := map[string]int{}
 	for i, field := range fields {
-		genComment(out, field.CommentLines, "  ")
-		fmt.Fprintf(out, "  ")
-		switch {
-		case field.Map:
-		case field.Repeated:
-			fmt.Fprintf(out, "repeated ")
-		case field.Optional:
-			fmt.Fprintf(out, "optional ")
-		default:
-			fmt.Fprintf(out, "required ")
-		}
-		sw.Do(`$.Type|local$ $.Name$ = $.Tag$`, field)
-		if len(field.Extras) > 0 {
-			fmt.Fprintf(out, " [")
-			first := true
-			for k, v := range field.Extras {
-				if first {
-					first = false
-				} else {
-					fmt.Fprintf(out, ", ")
-				}
-				fmt.Fprintf(out, "%s = %s", k, v)
+		prefix := b.renderFieldPrefix(field, len(field.OneofGroup) == 0)
+		prefixes[i] = prefix
+		if len(field.OneofGroup) == 0 {
+			if len(prefix) > topWidth {
+				topWidth = len(prefix)
+			}
+		} else if len(prefix) > oneofWidths[field.OneofGroup] {
+			oneofWidths[field.OneofGroup] = len(prefix)
+		}
+	}
+
+	// walk fields grouping consecutive members that share a OneofGroup into
+	// a single `oneof` block; ungrouped fields are emitted as before.
+	for i := 0; i < len(fields); {
+		field := fields[i]
+		if len(field.OneofGroup) == 0 {
+			b.writeField(out, field, "  ", prefixes[i], topWidth)
+			i++
+			if i != len(fields) {
+				fmt.Fprintln(out)
 			}
-			fmt.Fprintf(out, "]")
+			continue
+		}
+		group := field.OneofGroup
+		j := i
+		for j < len(fields) && fields[j].OneofGroup == group {
+			j++
+		}
+		fmt.Fprintf(out, "  oneof %s {\n", group)
+		for k := i; k < j; k++ {
+			b.writeField(out, fields[k], "    ", prefixes[k], oneofWidths[group])
+		}
+		fmt.Fprintf(out, "  }\n")
+		i = j
+		if i != len(fields) {
+			fmt.Fprintln(out)
+		}
+	}
+	fmt.Fprintf(out, "}\n\n")
+}
+
+// formatReservedTags renders a set of tag numbers as a proto `reserved`
+// argument list, collapsing consecutive runs into "N to M" ranges the way
+// protoc's own formatter does.
+func formatReservedTags(tags []int) string {
+	sort.Ints(tags)
+	var parts []string
+	for i := 0; i < len(tags); {
+		j := i
+		for j+1 < len(tags) && tags[j+1] == tags[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, strconv.Itoa(tags[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d to %d", tags[i], tags[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderFieldPrefix renders the portion of a field declaration before its
+// `= tag`, i.e. any required/repeated/optional keyword plus `Type Name`, so
+// callers can measure it for column alignment. allowModifier is false for
+// fields inside a `oneof` block, which may carry none of those keywords.
+func (b bodyGen) renderFieldPrefix(field protoField, allowModifier bool) string {
+	buf := &bytes.Buffer{}
+	switch {
+	case !allowModifier:
+	case field.Map:
+	case field.Repeated:
+		buf.WriteString("repeated ")
+	case b.protoSyntax == protoSyntaxProto3:
+		// proto3 dropped the required/optional keywords; singular
+		// scalar fields are implicitly optional.
+	case field.Optional:
+		buf.WriteString("optional ")
+	default:
+		buf.WriteString("required ")
+	}
+	generator.NewSnippetWriter(buf, b.context, "$", "$").Do(`$.Type|local$ $.Name$`, field)
+	return buf.String()
+}
+
+// writeField emits a single field declaration at the given indent, padding
+// prefix out to width columns so the `=` signs line up within a message.
+func (b bodyGen) writeField(out io.Writer, field protoField, indent, prefix string, width int) {
+	genComment(out, field.CommentLines, indent)
+	fmt.Fprint(out, indent)
+	fmt.Fprint(out, prefix)
+	if pad := width - len(prefix); pad > 0 {
+		fmt.Fprint(out, strings.Repeat(" ", pad))
+	}
+	fmt.Fprintf(out, " = %d", field.Tag)
+	if len(field.Extras) > 0 {
+		keys := make([]string, 0, len(field.Extras))
+		for k := range field.Extras {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(out, " [")
+		for i, k := range keys {
+			if i > 0 {
+				fmt.Fprintf(out, ", ")
+			}
+			fmt.Fprintf(out, "%s = %s", k, field.Extras[k])
+		}
+		fmt.Fprintf(out, "]")
+	}
+	fmt.Fprintf(out, ";\n")
+}
+
+// doService emits a `service` block for an interface tagged
+// +genprotoidl.service=true. Each exported method becomes an rpc; its
+// request and response must resolve to message types via the locator.
+func (b bodyGen) doService(sw *generator.SnippetWriter) {
+	if len(b.t.Name.Name) == 0 {
+		return
+	}
+	if isPrivateGoName(b.t.Name.Name) {
+		return
+	}
+
+	names := make([]string, 0, len(b.t.Methods))
+	for name := range b.t.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := sw.Out()
+	genComment(out, b.t.CommentLines, "")
+	fmt.Fprintf(out, "service %s {\n", b.t.Name.Name)
+	for _, name := range names {
+		if isPrivateGoName(name) {
+			continue
 		}
-		fmt.Fprintf(out, ";\n")
-		if i != len(fields)-1 {
-			fmt.Fprintf(out, "\n")
+		rpc, err := b.methodToRPC(name, b.t.Methods[name])
+		if err != nil {
+			fmt.Fprintf(out, "  // ERROR: method %s cannot be converted to an rpc: %v\n", name, err)
+			continue
 		}
+		genComment(out, rpc.CommentLines, "  ")
+		fmt.Fprintf(out, "  rpc %s(%s%s) returns (%s%s);\n", rpc.Name, rpc.RequestStream, rpc.RequestType, rpc.ResponseStream, rpc.ResponseType)
 	}
 	fmt.Fprintf(out, "}\n\n")
 }
 
+type protoRPC struct {
+	Name           string
+	RequestType    string
+	ResponseType   string
+	RequestStream  string
+	ResponseStream string
+	CommentLines   string
+}
+
+// methodToRPC resolves an interface method's first struct parameter and
+// first struct result to protobuf message types, honoring a per-method
+// +genprotoidl.rpc.stream=server|client|bidi comment tag.
+func (b bodyGen) methodToRPC(name string, m *types.Type) (protoRPC, error) {
+	if m.Kind != types.Func || m.Signature == nil {
+		return protoRPC{}, fmt.Errorf("method %q is not a function", name)
+	}
+	sig := m.Signature
+
+	reqType := firstStructType(sig.Parameters)
+	if reqType == nil {
+		return protoRPC{}, fmt.Errorf("method %q has no struct request parameter", name)
+	}
+	respType := firstStructType(sig.Results)
+	if respType == nil {
+		return protoRPC{}, fmt.Errorf("method %q has no struct response result", name)
+	}
+
+	reqProto, err := b.locator.ProtoTypeFor(reqType)
+	if err != nil {
+		return protoRPC{}, fmt.Errorf("request type %q: %v", reqType.Name, err)
+	}
+	respProto, err := b.locator.ProtoTypeFor(respType)
+	if err != nil {
+		return protoRPC{}, fmt.Errorf("response type %q: %v", respType.Name, err)
+	}
+
+	reqStream, respStream := "", ""
+	switch types.ExtractCommentTags("+", m.CommentLines)["genprotoidl.rpc.stream"] {
+	case "client":
+		reqStream = "stream "
+	case "server":
+		respStream = "stream "
+	case "bidi":
+		reqStream, respStream = "stream ", "stream "
+	}
+
+	return protoRPC{
+		Name:           name,
+		RequestType:    reqProto.Name.Name,
+		ResponseType:   respProto.Name.Name,
+		RequestStream:  reqStream,
+		ResponseStream: respStream,
+		CommentLines:   m.CommentLines,
+	}, nil
+}
+
+// firstStructType returns the first parameter/result in params that is a
+// struct (dereferencing a single pointer), or nil if none qualifies.
+func firstStructType(params []*types.Type) *types.Type {
+	for _, p := range params {
+		t := p
+		if t.Kind == types.Pointer {
+			t = t.Elem
+		}
+		if t.Kind == types.Struct && len(t.Name.Name) != 0 {
+			return t
+		}
+	}
+	return nil
+}
+
+// ResolvedField is the generator-agnostic view of a single message field:
+// enough for a template to render OpenAPI, TypeScript, docs, etc. without
+// caring how .proto text happens to be laid out.
+type ResolvedField struct {
+	Name       string
+	Type       string
+	Tag        int
+	Repeated   bool
+	Map        bool
+	Nullable   bool
+	OneofGroup string
+	Comment    string
+}
+
+// ResolvedMessage is the generator-agnostic IR for one Go struct: its
+// walked members plus whatever +genprotoidl.options.* applied to it.
+// genProtoIDL records this IR while it writes .proto text from the same
+// walked fields, and genTemplate renders user templates from it, so adding
+// an output format never requires re-walking types.
+type ResolvedMessage struct {
+	Name    string
+	Comment string
+	Fields  []ResolvedField
+	Options []string
+}
+
+// toResolvedMessage converts the already-walked fields for b.t into the
+// generator-agnostic IR.
+func (b bodyGen) toResolvedMessage(fields []protoField, options []string) ResolvedMessage {
+	rm := ResolvedMessage{
+		Name:    b.t.Name.Name,
+		Comment: strings.TrimSpace(b.t.CommentLines),
+		Options: options,
+		Fields:  make([]ResolvedField, 0, len(fields)),
+	}
+	for _, f := range fields {
+		rm.Fields = append(rm.Fields, ResolvedField{
+			Name:       f.Name,
+			Type:       f.Type.Name.Name,
+			Tag:        f.Tag,
+			Repeated:   f.Repeated,
+			Map:        f.Map,
+			Nullable:   f.Nullable,
+			OneofGroup: f.OneofGroup,
+			Comment:    strings.TrimSpace(f.CommentLines),
+		})
+	}
+	return rm
+}
+
 type protoField struct {
 	LocalPackage types.Name
 
@@ -314,6 +747,11 @@ type protoField struct {
 	Nullable bool
 	Extras   map[string]string
 
+	// OneofGroup is non-empty when this field belongs to a `oneof` block,
+	// set by a +genprotoidl.oneof=<groupName> tag (or auto-assigned by
+	// +genprotoidl.union=true). Fields sharing a group must be contiguous.
+	OneofGroup string
+
 	CommentLines string
 
 	OptionalSet bool
@@ -323,15 +761,59 @@ var (
 	errUnrecognizedType = fmt.Errorf("did not recognize the provided type")
 )
 
+// wellKnownProtoType maps a Go type onto one of protobuf's well-known
+// types, plus the .proto file that has to be imported for the mapping to
+// resolve.
+type wellKnownProtoType struct {
+	goName     types.Name
+	protoName  types.Name
+	importPath string
+}
+
+// wellKnownProtoTypes is the configurable set of Go -> well-known-type
+// mappings isFundamentalProtoType recognizes. Add an entry here to teach
+// the generator about another well-known type.
+var wellKnownProtoTypes = []wellKnownProtoType{
+	{
+		goName:     types.Name{Package: "time", Name: "Time"},
+		protoName:  types.Name{Package: "google.protobuf", Name: "Timestamp"},
+		importPath: "google/protobuf/timestamp.proto",
+	},
+	{
+		goName:     types.Name{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "Duration"},
+		protoName:  types.Name{Package: "google.protobuf", Name: "Duration"},
+		importPath: "google/protobuf/duration.proto",
+	},
+}
+
 func isFundamentalProtoType(t *types.Type) (*types.Type, bool) {
-	// switch {
-	// case t.Kind == types.Struct && t.Name == types.Name{Package: "time", Name: "Time"}:
-	// 	return &types.Type{
-	// 		Kind: typesKindProtobuf,
-	// 		Name: types.Name{Path: "google/protobuf/timestamp.proto", Package: "google.protobuf", Name: "Timestamp"},
-	// 	}, true
-	// }
 	switch t.Kind {
+	case types.Struct:
+		// an anonymous, memberless struct{} carries no information of its
+		// own; protobuf models that as google.protobuf.Empty.
+		if len(t.Name.Name) == 0 && len(t.Members) == 0 {
+			return &types.Type{
+				Kind: typesKindProtobuf,
+				Name: types.Name{Path: "google/protobuf/empty.proto", Package: "google.protobuf", Name: "Empty"},
+			}, true
+		}
+		for _, wk := range wellKnownProtoTypes {
+			if t.Name == wk.goName {
+				return &types.Type{
+					Kind: typesKindProtobuf,
+					Name: types.Name{Path: wk.importPath, Package: wk.protoName.Package, Name: wk.protoName.Name},
+				}, true
+			}
+		}
+	case types.Map:
+		// map[string]interface{} has no fixed protobuf shape; model it as
+		// google.protobuf.Struct rather than a proto map.
+		if t.Key.Name.Name == "string" && t.Elem.Kind == types.Interface {
+			return &types.Type{
+				Kind: typesKindProtobuf,
+				Name: types.Name{Path: "google/protobuf/struct.proto", Package: "google.protobuf", Name: "Struct"},
+			}, true
+		}
 	case types.Slice:
 		if t.Elem.Name.Name == "byte" && len(t.Elem.Name.Package) == 0 {
 			return &types.Type{Name: types.Name{Name: "bytes"}, Kind: typesKindProtobuf}, true
@@ -368,6 +850,12 @@ func memberTypeToProtobufField(locator ProtobufLocator, field *protoField, t *ty
 	case types.Builtin:
 		field.Type, err = locator.ProtoTypeFor(t)
 	case types.Map:
+		// some maps (e.g. map[string]interface{}) have a well-known
+		// protobuf mapping of their own rather than becoming a proto map.
+		if _, ok := isFundamentalProtoType(t); ok {
+			field.Type, err = locator.ProtoTypeFor(t)
+			return err
+		}
 		valueField := &protoField{}
 		if err := memberTypeToProtobufField(locator, valueField, t.Elem); err != nil {
 			return err
@@ -416,7 +904,11 @@ func memberTypeToProtobufField(locator ProtobufLocator, field *protoField, t *ty
 		field.Repeated = true
 	case types.Struct:
 		if len(t.Name.Name) == 0 {
-			return errUnrecognizedType
+			// only a well-known mapping (e.g. struct{} -> google.protobuf.Empty)
+			// can save an otherwise-anonymous struct.
+			if _, ok := isFundamentalProtoType(t); !ok {
+				return errUnrecognizedType
+			}
 		}
 		field.Type, err = locator.ProtoTypeFor(t)
 		field.Nullable = false
@@ -494,9 +986,11 @@ func protobufTagToField(tag string, field *protoField, m types.Member, t *types.
 	return nil
 }
 
-func membersToFields(locator ProtobufLocator, t *types.Type, localPackage types.Name) ([]protoField, error) {
+func membersToFields(locator ProtobufLocator, t *types.Type, localPackage types.Name, previousTags map[string]int) ([]protoField, error) {
 	fields := []protoField{}
 
+	autoUnion := types.ExtractCommentTags("+", t.CommentLines)["genprotoidl.union"] == "true"
+
 	for _, m := range t.Members {
 		if isPrivateGoName(m.Name) {
 			// skip private fields
@@ -555,7 +1049,15 @@ func membersToFields(locator ProtobufLocator, t *types.Type, localPackage types.
 		//	field.Nullable = false
 		//}
 
-		if !field.Nullable {
+		if group := types.ExtractCommentTags("+", m.CommentLines)["genprotoidl.oneof"]; len(group) > 0 {
+			field.OneofGroup = group
+		} else if autoUnion && field.Nullable && !field.Repeated && !field.Map {
+			field.OneofGroup = "union"
+		}
+
+		// oneof members can't carry the nullable extension: oneof already
+		// implies at most one of the group's fields is ever set.
+		if !field.Nullable && len(field.OneofGroup) == 0 {
 			field.Extras["(gogoproto.nullable)"] = "false"
 		}
 		if (field.Type.Name.Name == "bytes" && field.Type.Name.Package == "") || (field.Repeated && field.Type.Name.Package == "" && isPrivateGoName(field.Type.Name.Name)) {
@@ -564,6 +1066,9 @@ func membersToFields(locator ProtobufLocator, t *types.Type, localPackage types.
 		if field.Name != m.Name {
 			field.Extras["(gogoproto.customname)"] = strconv.Quote(m.Name)
 		}
+		if types.ExtractCommentTags("+", m.CommentLines)["genprotoidl.deprecated"] == "true" {
+			field.Extras["deprecated"] = "true"
+		}
 		field.CommentLines = m.CommentLines
 		fields = append(fields, field)
 	}
@@ -585,12 +1090,32 @@ func membersToFields(locator ProtobufLocator, t *types.Type, localPackage types.
 			highest = tag
 		}
 	}
-	// starting from the highest observed tag, assign new field tags
+	// tags from the previous generation count as observed too, so a tag
+	// whose field was removed (and which doStruct reserves) is never handed
+	// to a new field.
+	for _, tag := range previousTags {
+		if tag > highest {
+			highest = tag
+		}
+	}
+	// starting from the highest observed tag, assign new field tags. Prefer
+	// whatever tag this field carried in a previous generation, so long as
+	// it isn't already spoken for, to keep regeneration wire-compatible.
 	for i := range fields {
 		field := &fields[i]
 		if field.Tag != -1 {
 			continue
 		}
+		if prev, ok := previousTags[field.Name]; ok {
+			if _, taken := byTag[prev]; !taken {
+				field.Tag = prev
+				byTag[prev] = field
+				if prev > highest {
+					highest = prev
+				}
+				continue
+			}
+		}
 		highest++
 		field.Tag = highest
 		byTag[field.Tag] = field
@@ -612,7 +1137,12 @@ func genComment(out io.Writer, comment, indent string) {
 	}
 }
 
-type protoIDLFileType struct{}
+// protoIDLFileType assembles the final .proto file for a package. protoSyntax
+// mirrors the generator's --proto-syntax flag so the emitted `syntax`
+// declaration matches the rest of the file.
+type protoIDLFileType struct {
+	protoSyntax string
+}
 
 func (ft protoIDLFileType) AssembleFile(f *generator.File, pathname string) error {
 	log.Printf("Assembling IDL file %q", pathname)
@@ -637,14 +1167,23 @@ func (ft protoIDLFileType) AssembleFile(f *generator.File, pathname string) erro
 func (ft protoIDLFileType) assemble(w io.Writer, f *generator.File) {
 	w.Write(f.Header)
 
-	fmt.Fprint(w, "syntax = 'proto2';\n\n")
+	if ft.protoSyntax == protoSyntaxProto3 {
+		fmt.Fprint(w, "syntax = \"proto3\";\n\n")
+	} else {
+		fmt.Fprint(w, "syntax = 'proto2';\n\n")
+	}
 
 	if len(f.PackageName) > 0 {
 		fmt.Fprintf(w, "package %v;\n\n", f.PackageName)
 	}
 
 	if len(f.Imports) > 0 {
+		imports := make([]string, 0, len(f.Imports))
 		for i := range f.Imports {
+			imports = append(imports, i)
+		}
+		sort.Strings(imports)
+		for _, i := range imports {
 			fmt.Fprintf(w, "import %q;\n", i)
 		}
 		fmt.Fprint(w, "\n")
@@ -675,3 +1214,54 @@ func isPrivateGoName(name string) bool {
 	}
 	return strings.ToLower(name[:1]) == name[:1]
 }
+
+// genTemplate renders another generator's resolved IR through user-supplied
+// text/template files under templateDir, one output file per template, into
+// templateOut. This lets teams produce OpenAPI schemas, TypeScript types,
+// docs, etc. from the same Go type walk that produces .proto, instead of
+// forking the generator for every output format. Templates see a
+// []ResolvedMessage as their root data.
+type genTemplate struct {
+	generator.DefaultGen
+
+	source      *genProtoIDL
+	templateDir string
+	templateOut string
+}
+
+// Filter excludes genTemplate from the normal per-type walk: it renders
+// once, from source's already-resolved IR, rather than once per type.
+func (g *genTemplate) Filter(c *generator.Context, t *types.Type) bool {
+	return false
+}
+
+// Finalize runs after source has walked every type in the package: it
+// renders each *.tmpl file in templateDir against source.ResolvedMessages().
+func (g *genTemplate) Finalize(c *generator.Context, w io.Writer) error {
+	if len(g.templateDir) == 0 {
+		return nil
+	}
+	templates, err := filepath.Glob(filepath.Join(g.templateDir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	messages := g.source.ResolvedMessages()
+	for _, tmplPath := range templates {
+		name := filepath.Base(tmplPath)
+		tmpl, err := template.New(name).ParseFiles(tmplPath)
+		if err != nil {
+			return fmt.Errorf("parsing template %q: %v", tmplPath, err)
+		}
+		outPath := filepath.Join(g.templateOut, strings.TrimSuffix(name, ".tmpl"))
+		out, err := os.Create(outPath)
+		if err != nil {
+			return err
+		}
+		err = tmpl.ExecuteTemplate(out, name, messages)
+		out.Close()
+		if err != nil {
+			return fmt.Errorf("rendering template %q: %v", tmplPath, err)
+		}
+	}
+	return nil
+}
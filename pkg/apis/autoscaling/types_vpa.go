@@ -0,0 +1,174 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file holds the internal (unversioned) VerticalPodAutoscaler types.
+// They live alongside the HorizontalPodAutoscaler types this package already
+// defines, since the two share a target-resource model even though they
+// drive scaling along different axes (replica count vs. per-container
+// requests).
+
+package autoscaling
+
+import (
+	api "k8s.io/kubernetes/pkg/api"
+	unversioned "k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// VerticalPodAutoscaler automatically manages the CPU and memory requests
+// of the pods owned by a target controller, recommending (and, per
+// UpdatePolicy, applying) per-container resource requests based on observed
+// historical usage instead of a fixed replica count.
+type VerticalPodAutoscaler struct {
+	unversioned.TypeMeta
+	api.ObjectMeta
+
+	Spec   VerticalPodAutoscalerSpec
+	Status VerticalPodAutoscalerStatus
+}
+
+// VerticalPodAutoscalerList is a list of VerticalPodAutoscalers.
+type VerticalPodAutoscalerList struct {
+	unversioned.TypeMeta
+	unversioned.ListMeta
+
+	Items []VerticalPodAutoscaler
+}
+
+// VerticalPodAutoscalerSpec describes the desired behavior of a
+// VerticalPodAutoscaler.
+type VerticalPodAutoscalerSpec struct {
+	// TargetRef points at the resource whose pods' resource requests this
+	// autoscaler manages, e.g. a Deployment or ReplicaSet.
+	TargetRef CrossVersionObjectReference
+
+	// UpdatePolicy controls whether and how recommendations are applied to
+	// running pods. Defaults to Auto.
+	UpdatePolicy PodUpdatePolicy
+
+	// ResourcePolicy constrains the recommendation per container; a
+	// container with no matching ContainerResourcePolicy uses the
+	// DefaultContainerResourcePolicyName ("*") entry, if any.
+	ResourcePolicy PodResourcePolicy
+}
+
+// CrossVersionObjectReference identifies the controller a VerticalPodAutoscaler
+// (or HorizontalPodAutoscaler) targets.
+type CrossVersionObjectReference struct {
+	Kind       string
+	Name       string
+	APIVersion string
+}
+
+// UpdateMode describes how a VerticalPodAutoscaler applies its
+// recommendations to running pods.
+type UpdateMode string
+
+const (
+	// UpdateModeOff means recommendations are computed but never applied;
+	// this is the read-only "recommendation only" mode.
+	UpdateModeOff UpdateMode = "Off"
+	// UpdateModeInitial only applies the recommendation when a pod is
+	// created; existing pods are left alone until they're replaced for some
+	// other reason.
+	UpdateModeInitial UpdateMode = "Initial"
+	// UpdateModeRecreate evicts and recreates running pods whose requests
+	// fall outside the recommendation window so the new pod picks up the
+	// current recommendation via admission.
+	UpdateModeRecreate UpdateMode = "Recreate"
+	// UpdateModeAuto is like Recreate today (VPA has no in-place resize yet)
+	// but is the mode that will switch to in-place updates once available.
+	UpdateModeAuto UpdateMode = "Auto"
+)
+
+// PodUpdatePolicy specifies how recommendations are applied to pods.
+type PodUpdatePolicy struct {
+	UpdateMode *UpdateMode
+}
+
+// DefaultContainerResourcePolicyName is the special ContainerResourcePolicy
+// name that applies to every container not otherwise named in
+// PodResourcePolicy.ContainerPolicies.
+const DefaultContainerResourcePolicyName = "*"
+
+// PodResourcePolicy controls how the autoscaler computes recommended
+// resources for the containers of the target pods.
+type PodResourcePolicy struct {
+	ContainerPolicies []ContainerResourcePolicy
+}
+
+// ContainerResourcePolicy constrains the recommendation for one container
+// (or, using DefaultContainerResourcePolicyName, every container without a
+// more specific entry).
+type ContainerResourcePolicy struct {
+	ContainerName string
+
+	// ControlledResources lists which of api.ResourceCPU/api.ResourceMemory
+	// this autoscaler recommends for the container. A resource absent from
+	// this list is left for the user (or another autoscaler) to manage.
+	ControlledResources []api.ResourceName
+
+	MinAllowed api.ResourceList
+	MaxAllowed api.ResourceList
+}
+
+// VerticalPodAutoscalerStatus describes the most recently computed
+// recommendation.
+type VerticalPodAutoscalerStatus struct {
+	Recommendation *RecommendedPodResources
+	// Conditions surface terminal state, such as the VPA/HPA CPU-or-memory
+	// conflict described on ConfigUnsupported.
+	Conditions []VerticalPodAutoscalerCondition
+}
+
+// RecommendedPodResources is the last computed recommendation, one entry
+// per observed container.
+type RecommendedPodResources struct {
+	ContainerRecommendations []RecommendedContainerResources
+}
+
+// RecommendedContainerResources carries the lower/target/upper bound
+// recommendation for a single container, as produced by the exponentially
+// decayed usage histogram: target is the recommended request, and
+// lower/upper bound the window the Updater tolerates before evicting.
+type RecommendedContainerResources struct {
+	ContainerName string
+	Target        api.ResourceList
+	LowerBound    api.ResourceList
+	UpperBound    api.ResourceList
+}
+
+// VerticalPodAutoscalerConditionType is a valid value for
+// VerticalPodAutoscalerCondition.Type.
+type VerticalPodAutoscalerConditionType string
+
+const (
+	// ConfigUnsupported is set when TargetRef's VPA and HPA both manage CPU
+	// or memory on the same controller; the autoscaler is disabled rather
+	// than fighting the HPA over those resources.
+	ConfigUnsupported VerticalPodAutoscalerConditionType = "ConfigUnsupported"
+	// RecommendationProvided is set once the Recommender has produced at
+	// least one recommendation.
+	RecommendationProvided VerticalPodAutoscalerConditionType = "RecommendationProvided"
+)
+
+// VerticalPodAutoscalerCondition is a single condition on a
+// VerticalPodAutoscaler's status.
+type VerticalPodAutoscalerCondition struct {
+	Type    VerticalPodAutoscalerConditionType
+	Status  api.ConditionStatus
+	Reason  string
+	Message string
+}
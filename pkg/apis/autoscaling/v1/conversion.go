@@ -0,0 +1,236 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	api "k8s.io/kubernetes/pkg/api"
+	v2 "k8s.io/kubernetes/pkg/apis/autoscaling/v2"
+	conversion "k8s.io/kubernetes/pkg/conversion"
+)
+
+const (
+	// HorizontalPodAutoscalerSpecMetricsAnnotationName holds the full,
+	// JSON-serialized v2 HorizontalPodAutoscalerSpec.Metrics list on a v1
+	// HorizontalPodAutoscaler, since v1 can only natively represent a single
+	// CPU utilization target. Converting v1 back to v2 consults this
+	// annotation before falling back to TargetCPUUtilizationPercentage, so a
+	// v2 object round-trips through v1 (and through any client that only
+	// understands v1) without losing its other metrics.
+	HorizontalPodAutoscalerSpecMetricsAnnotationName = "autoscaling.alpha.kubernetes.io/metrics"
+
+	// HorizontalPodAutoscalerStatusMetricsAnnotationName is the status
+	// equivalent of HorizontalPodAutoscalerSpecMetricsAnnotationName,
+	// carrying the v2 HorizontalPodAutoscalerStatus.CurrentMetrics list.
+	HorizontalPodAutoscalerStatusMetricsAnnotationName = "autoscaling.alpha.kubernetes.io/current-metrics"
+)
+
+func init() {
+	if err := api.Scheme.AddConversionFuncs(
+		Convert_v2_HorizontalPodAutoscaler_To_v1_HorizontalPodAutoscaler,
+		Convert_v1_HorizontalPodAutoscaler_To_v2_HorizontalPodAutoscaler,
+	); err != nil {
+		// if one of the conversion functions is malformed, detect it immediately.
+		panic(err)
+	}
+}
+
+// Convert_v2_HorizontalPodAutoscaler_To_v1_HorizontalPodAutoscaler is
+// hand-written, not autogenerated: unlike the symmetric conversions in
+// conversion_generated.go, this one is lossy in one direction. v1 only has
+// room for a single CPU-utilization target, so the full v2 Metrics list is
+// stashed in the HorizontalPodAutoscalerSpecMetricsAnnotationName annotation
+// (and CurrentMetrics in its status counterpart) for
+// Convert_v1_HorizontalPodAutoscaler_To_v2_HorizontalPodAutoscaler to
+// reconstitute; the CPU Resource metric, if present, is also projected onto
+// TargetCPUUtilizationPercentage so v1-only clients still see it.
+func Convert_v2_HorizontalPodAutoscaler_To_v1_HorizontalPodAutoscaler(in *v2.HorizontalPodAutoscaler, out *HorizontalPodAutoscaler, s conversion.Scope) error {
+	if err := api.Convert_unversioned_TypeMeta_To_unversioned_TypeMeta(&in.TypeMeta, &out.TypeMeta, s); err != nil {
+		return err
+	}
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ObjectMeta, &out.ObjectMeta, 0); err != nil {
+		return err
+	}
+
+	out.Spec.ScaleTargetRef.Kind = in.Spec.ScaleTargetRef.Kind
+	out.Spec.ScaleTargetRef.Name = in.Spec.ScaleTargetRef.Name
+	out.Spec.ScaleTargetRef.APIVersion = in.Spec.ScaleTargetRef.APIVersion
+	out.Spec.MinReplicas = in.Spec.MinReplicas
+	out.Spec.MaxReplicas = in.Spec.MaxReplicas
+	out.Spec.TargetCPUUtilizationPercentage = cpuUtilizationTargetFromMetrics(in.Spec.Metrics)
+	if len(in.Spec.Metrics) > 0 {
+		encoded, err := json.Marshal(in.Spec.Metrics)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metrics for the %q annotation: %v", HorizontalPodAutoscalerSpecMetricsAnnotationName, err)
+		}
+		setAnnotation(&out.ObjectMeta, HorizontalPodAutoscalerSpecMetricsAnnotationName, string(encoded))
+	}
+
+	out.Status.ObservedGeneration = in.Status.ObservedGeneration
+	out.Status.LastScaleTime = in.Status.LastScaleTime
+	out.Status.CurrentReplicas = in.Status.CurrentReplicas
+	out.Status.DesiredReplicas = in.Status.DesiredReplicas
+	out.Status.CurrentCPUUtilizationPercentage = currentCPUUtilizationFromMetrics(in.Status.CurrentMetrics)
+	if len(in.Status.CurrentMetrics) > 0 {
+		encoded, err := json.Marshal(in.Status.CurrentMetrics)
+		if err != nil {
+			return fmt.Errorf("failed to marshal current metrics for the %q annotation: %v", HorizontalPodAutoscalerStatusMetricsAnnotationName, err)
+		}
+		setAnnotation(&out.ObjectMeta, HorizontalPodAutoscalerStatusMetricsAnnotationName, string(encoded))
+	}
+	return nil
+}
+
+// setAnnotation sets meta.Annotations[name], allocating the map if needed.
+func setAnnotation(meta *api.ObjectMeta, name, value string) {
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string, 1)
+	}
+	meta.Annotations[name] = value
+}
+
+// Convert_v1_HorizontalPodAutoscaler_To_v2_HorizontalPodAutoscaler is the
+// reverse of Convert_v2_HorizontalPodAutoscaler_To_v1_HorizontalPodAutoscaler.
+// It first consults the metrics annotations left behind by that conversion
+// and only falls back to the CPU-only fields when an annotation is absent
+// (e.g. the object was created directly against v1, or by a peer apiserver
+// that predates v2).
+func Convert_v1_HorizontalPodAutoscaler_To_v2_HorizontalPodAutoscaler(in *HorizontalPodAutoscaler, out *v2.HorizontalPodAutoscaler, s conversion.Scope) error {
+	if err := api.Convert_unversioned_TypeMeta_To_unversioned_TypeMeta(&in.TypeMeta, &out.TypeMeta, s); err != nil {
+		return err
+	}
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ObjectMeta, &out.ObjectMeta, 0); err != nil {
+		return err
+	}
+
+	out.Spec.ScaleTargetRef.Kind = in.Spec.ScaleTargetRef.Kind
+	out.Spec.ScaleTargetRef.Name = in.Spec.ScaleTargetRef.Name
+	out.Spec.ScaleTargetRef.APIVersion = in.Spec.ScaleTargetRef.APIVersion
+	out.Spec.MinReplicas = in.Spec.MinReplicas
+	out.Spec.MaxReplicas = in.Spec.MaxReplicas
+	metrics, err := metricsFromAnnotation(out.ObjectMeta.Annotations, HorizontalPodAutoscalerSpecMetricsAnnotationName, in.Spec.TargetCPUUtilizationPercentage != nil)
+	if err != nil {
+		return err
+	}
+	if metrics != nil {
+		out.Spec.Metrics = metrics
+	} else if in.Spec.TargetCPUUtilizationPercentage != nil {
+		out.Spec.Metrics = []v2.MetricSpec{cpuUtilizationMetricSpec(*in.Spec.TargetCPUUtilizationPercentage)}
+	}
+	delete(out.ObjectMeta.Annotations, HorizontalPodAutoscalerSpecMetricsAnnotationName)
+
+	out.Status.ObservedGeneration = in.Status.ObservedGeneration
+	out.Status.LastScaleTime = in.Status.LastScaleTime
+	out.Status.CurrentReplicas = in.Status.CurrentReplicas
+	out.Status.DesiredReplicas = in.Status.DesiredReplicas
+	currentMetrics, err := currentMetricsFromAnnotation(out.ObjectMeta.Annotations, in.Status.CurrentCPUUtilizationPercentage != nil)
+	if err != nil {
+		return err
+	}
+	if currentMetrics != nil {
+		out.Status.CurrentMetrics = currentMetrics
+	} else if in.Status.CurrentCPUUtilizationPercentage != nil {
+		out.Status.CurrentMetrics = []v2.MetricStatus{cpuUtilizationMetricStatus(*in.Status.CurrentCPUUtilizationPercentage)}
+	}
+	delete(out.ObjectMeta.Annotations, HorizontalPodAutoscalerStatusMetricsAnnotationName)
+
+	return nil
+}
+
+// metricsFromAnnotation decodes HorizontalPodAutoscalerSpecMetricsAnnotationName
+// back into a v2.MetricSpec list. A missing annotation always yields nil, and
+// the caller falls back to the CPU field. hasLegacyFallback only decides what
+// happens to an annotation that fails to unmarshal: with a CPU field to fall
+// back to it is ignored (nil, no error), otherwise it is reported as an error.
+func metricsFromAnnotation(annotations map[string]string, name string, hasLegacyFallback bool) ([]v2.MetricSpec, error) {
+	encoded, ok := annotations[name]
+	if !ok {
+		return nil, nil
+	}
+	var metrics []v2.MetricSpec
+	if err := json.Unmarshal([]byte(encoded), &metrics); err != nil {
+		if hasLegacyFallback {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to unmarshal the %q annotation: %v", name, err)
+	}
+	return metrics, nil
+}
+
+func currentMetricsFromAnnotation(annotations map[string]string, hasLegacyFallback bool) ([]v2.MetricStatus, error) {
+	encoded, ok := annotations[HorizontalPodAutoscalerStatusMetricsAnnotationName]
+	if !ok {
+		return nil, nil
+	}
+	var metrics []v2.MetricStatus
+	if err := json.Unmarshal([]byte(encoded), &metrics); err != nil {
+		if hasLegacyFallback {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to unmarshal the %q annotation: %v", HorizontalPodAutoscalerStatusMetricsAnnotationName, err)
+	}
+	return metrics, nil
+}
+
+func cpuUtilizationMetricSpec(targetAverageUtilization int32) v2.MetricSpec {
+	return v2.MetricSpec{
+		Type: v2.ResourceMetricSourceType,
+		Resource: &v2.ResourceMetricSource{
+			Name:                     api.ResourceCPU,
+			TargetAverageUtilization: &targetAverageUtilization,
+		},
+	}
+}
+
+func cpuUtilizationMetricStatus(currentAverageUtilization int32) v2.MetricStatus {
+	return v2.MetricStatus{
+		Type: v2.ResourceMetricSourceType,
+		Resource: &v2.ResourceMetricStatus{
+			Name:                      api.ResourceCPU,
+			CurrentAverageUtilization: &currentAverageUtilization,
+		},
+	}
+}
+
+// cpuUtilizationTargetFromMetrics finds the CPU Resource metric, if any, in
+// a v2 metrics list and projects it onto v1's single
+// TargetCPUUtilizationPercentage field.
+func cpuUtilizationTargetFromMetrics(metrics []v2.MetricSpec) *int32 {
+	for _, metric := range metrics {
+		if metric.Type == v2.ResourceMetricSourceType && metric.Resource != nil &&
+			metric.Resource.Name == api.ResourceCPU && metric.Resource.TargetAverageUtilization != nil {
+			target := *metric.Resource.TargetAverageUtilization
+			return &target
+		}
+	}
+	return nil
+}
+
+func currentCPUUtilizationFromMetrics(metrics []v2.MetricStatus) *int32 {
+	for _, metric := range metrics {
+		if metric.Type == v2.ResourceMetricSourceType && metric.Resource != nil &&
+			metric.Resource.Name == api.ResourceCPU && metric.Resource.CurrentAverageUtilization != nil {
+			current := *metric.Resource.CurrentAverageUtilization
+			return &current
+		}
+	}
+	return nil
+}
@@ -45,6 +45,14 @@ func init() {
 		Convert_autoscaling_ScaleSpec_To_v1_ScaleSpec,
 		Convert_v1_ScaleStatus_To_autoscaling_ScaleStatus,
 		Convert_autoscaling_ScaleStatus_To_v1_ScaleStatus,
+		Convert_v1_VerticalPodAutoscaler_To_autoscaling_VerticalPodAutoscaler,
+		Convert_autoscaling_VerticalPodAutoscaler_To_v1_VerticalPodAutoscaler,
+		Convert_v1_VerticalPodAutoscalerList_To_autoscaling_VerticalPodAutoscalerList,
+		Convert_autoscaling_VerticalPodAutoscalerList_To_v1_VerticalPodAutoscalerList,
+		Convert_v1_VerticalPodAutoscalerSpec_To_autoscaling_VerticalPodAutoscalerSpec,
+		Convert_autoscaling_VerticalPodAutoscalerSpec_To_v1_VerticalPodAutoscalerSpec,
+		Convert_v1_VerticalPodAutoscalerStatus_To_autoscaling_VerticalPodAutoscalerStatus,
+		Convert_autoscaling_VerticalPodAutoscalerStatus_To_v1_VerticalPodAutoscalerStatus,
 	); err != nil {
 		// if one of the conversion functions is malformed, detect it immediately.
 		panic(err)
@@ -320,3 +328,323 @@ func autoConvert_autoscaling_ScaleStatus_To_v1_ScaleStatus(in *autoscaling.Scale
 func Convert_autoscaling_ScaleStatus_To_v1_ScaleStatus(in *autoscaling.ScaleStatus, out *ScaleStatus, s conversion.Scope) error {
 	return autoConvert_autoscaling_ScaleStatus_To_v1_ScaleStatus(in, out, s)
 }
+
+func autoConvert_v1_VerticalPodAutoscaler_To_autoscaling_VerticalPodAutoscaler(in *VerticalPodAutoscaler, out *autoscaling.VerticalPodAutoscaler, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*VerticalPodAutoscaler))(in)
+	}
+	if err := api.Convert_unversioned_TypeMeta_To_unversioned_TypeMeta(&in.TypeMeta, &out.TypeMeta, s); err != nil {
+		return err
+	}
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ObjectMeta, &out.ObjectMeta, 0); err != nil {
+		return err
+	}
+	if err := Convert_v1_VerticalPodAutoscalerSpec_To_autoscaling_VerticalPodAutoscalerSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_VerticalPodAutoscalerStatus_To_autoscaling_VerticalPodAutoscalerStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func Convert_v1_VerticalPodAutoscaler_To_autoscaling_VerticalPodAutoscaler(in *VerticalPodAutoscaler, out *autoscaling.VerticalPodAutoscaler, s conversion.Scope) error {
+	return autoConvert_v1_VerticalPodAutoscaler_To_autoscaling_VerticalPodAutoscaler(in, out, s)
+}
+
+func autoConvert_autoscaling_VerticalPodAutoscaler_To_v1_VerticalPodAutoscaler(in *autoscaling.VerticalPodAutoscaler, out *VerticalPodAutoscaler, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*autoscaling.VerticalPodAutoscaler))(in)
+	}
+	if err := api.Convert_unversioned_TypeMeta_To_unversioned_TypeMeta(&in.TypeMeta, &out.TypeMeta, s); err != nil {
+		return err
+	}
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ObjectMeta, &out.ObjectMeta, 0); err != nil {
+		return err
+	}
+	if err := Convert_autoscaling_VerticalPodAutoscalerSpec_To_v1_VerticalPodAutoscalerSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_autoscaling_VerticalPodAutoscalerStatus_To_v1_VerticalPodAutoscalerStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func Convert_autoscaling_VerticalPodAutoscaler_To_v1_VerticalPodAutoscaler(in *autoscaling.VerticalPodAutoscaler, out *VerticalPodAutoscaler, s conversion.Scope) error {
+	return autoConvert_autoscaling_VerticalPodAutoscaler_To_v1_VerticalPodAutoscaler(in, out, s)
+}
+
+func autoConvert_v1_VerticalPodAutoscalerList_To_autoscaling_VerticalPodAutoscalerList(in *VerticalPodAutoscalerList, out *autoscaling.VerticalPodAutoscalerList, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*VerticalPodAutoscalerList))(in)
+	}
+	if err := api.Convert_unversioned_TypeMeta_To_unversioned_TypeMeta(&in.TypeMeta, &out.TypeMeta, s); err != nil {
+		return err
+	}
+	if err := api.Convert_unversioned_ListMeta_To_unversioned_ListMeta(&in.ListMeta, &out.ListMeta, s); err != nil {
+		return err
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]autoscaling.VerticalPodAutoscaler, len(*in))
+		for i := range *in {
+			if err := Convert_v1_VerticalPodAutoscaler_To_autoscaling_VerticalPodAutoscaler(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+func Convert_v1_VerticalPodAutoscalerList_To_autoscaling_VerticalPodAutoscalerList(in *VerticalPodAutoscalerList, out *autoscaling.VerticalPodAutoscalerList, s conversion.Scope) error {
+	return autoConvert_v1_VerticalPodAutoscalerList_To_autoscaling_VerticalPodAutoscalerList(in, out, s)
+}
+
+func autoConvert_autoscaling_VerticalPodAutoscalerList_To_v1_VerticalPodAutoscalerList(in *autoscaling.VerticalPodAutoscalerList, out *VerticalPodAutoscalerList, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*autoscaling.VerticalPodAutoscalerList))(in)
+	}
+	if err := api.Convert_unversioned_TypeMeta_To_unversioned_TypeMeta(&in.TypeMeta, &out.TypeMeta, s); err != nil {
+		return err
+	}
+	if err := api.Convert_unversioned_ListMeta_To_unversioned_ListMeta(&in.ListMeta, &out.ListMeta, s); err != nil {
+		return err
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VerticalPodAutoscaler, len(*in))
+		for i := range *in {
+			if err := Convert_autoscaling_VerticalPodAutoscaler_To_v1_VerticalPodAutoscaler(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+func Convert_autoscaling_VerticalPodAutoscalerList_To_v1_VerticalPodAutoscalerList(in *autoscaling.VerticalPodAutoscalerList, out *VerticalPodAutoscalerList, s conversion.Scope) error {
+	return autoConvert_autoscaling_VerticalPodAutoscalerList_To_v1_VerticalPodAutoscalerList(in, out, s)
+}
+
+func autoConvert_v1_VerticalPodAutoscalerSpec_To_autoscaling_VerticalPodAutoscalerSpec(in *VerticalPodAutoscalerSpec, out *autoscaling.VerticalPodAutoscalerSpec, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*VerticalPodAutoscalerSpec))(in)
+	}
+	out.TargetRef.Kind = in.TargetRef.Kind
+	out.TargetRef.Name = in.TargetRef.Name
+	out.TargetRef.APIVersion = in.TargetRef.APIVersion
+	if in.UpdatePolicy.UpdateMode != nil {
+		in, out := &in.UpdatePolicy.UpdateMode, &out.UpdatePolicy.UpdateMode
+		*out = new(autoscaling.UpdateMode)
+		**out = autoscaling.UpdateMode(**in)
+	} else {
+		out.UpdatePolicy.UpdateMode = nil
+	}
+	if in.ResourcePolicy.ContainerPolicies != nil {
+		in, out := &in.ResourcePolicy.ContainerPolicies, &out.ResourcePolicy.ContainerPolicies
+		*out = make([]autoscaling.ContainerResourcePolicy, len(*in))
+		for i := range *in {
+			if err := Convert_v1_ContainerResourcePolicy_To_autoscaling_ContainerResourcePolicy(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ResourcePolicy.ContainerPolicies = nil
+	}
+	return nil
+}
+
+func Convert_v1_VerticalPodAutoscalerSpec_To_autoscaling_VerticalPodAutoscalerSpec(in *VerticalPodAutoscalerSpec, out *autoscaling.VerticalPodAutoscalerSpec, s conversion.Scope) error {
+	return autoConvert_v1_VerticalPodAutoscalerSpec_To_autoscaling_VerticalPodAutoscalerSpec(in, out, s)
+}
+
+func autoConvert_autoscaling_VerticalPodAutoscalerSpec_To_v1_VerticalPodAutoscalerSpec(in *autoscaling.VerticalPodAutoscalerSpec, out *VerticalPodAutoscalerSpec, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*autoscaling.VerticalPodAutoscalerSpec))(in)
+	}
+	out.TargetRef.Kind = in.TargetRef.Kind
+	out.TargetRef.Name = in.TargetRef.Name
+	out.TargetRef.APIVersion = in.TargetRef.APIVersion
+	if in.UpdatePolicy.UpdateMode != nil {
+		in, out := &in.UpdatePolicy.UpdateMode, &out.UpdatePolicy.UpdateMode
+		*out = new(UpdateMode)
+		**out = UpdateMode(**in)
+	} else {
+		out.UpdatePolicy.UpdateMode = nil
+	}
+	if in.ResourcePolicy.ContainerPolicies != nil {
+		in, out := &in.ResourcePolicy.ContainerPolicies, &out.ResourcePolicy.ContainerPolicies
+		*out = make([]ContainerResourcePolicy, len(*in))
+		for i := range *in {
+			if err := Convert_autoscaling_ContainerResourcePolicy_To_v1_ContainerResourcePolicy(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ResourcePolicy.ContainerPolicies = nil
+	}
+	return nil
+}
+
+func Convert_autoscaling_VerticalPodAutoscalerSpec_To_v1_VerticalPodAutoscalerSpec(in *autoscaling.VerticalPodAutoscalerSpec, out *VerticalPodAutoscalerSpec, s conversion.Scope) error {
+	return autoConvert_autoscaling_VerticalPodAutoscalerSpec_To_v1_VerticalPodAutoscalerSpec(in, out, s)
+}
+
+func Convert_v1_ContainerResourcePolicy_To_autoscaling_ContainerResourcePolicy(in *ContainerResourcePolicy, out *autoscaling.ContainerResourcePolicy, s conversion.Scope) error {
+	out.ContainerName = in.ContainerName
+	if in.ControlledResources != nil {
+		in, out := &in.ControlledResources, &out.ControlledResources
+		*out = make([]api.ResourceName, len(*in))
+		copy(*out, *in)
+	} else {
+		out.ControlledResources = nil
+	}
+	out.MinAllowed = in.MinAllowed
+	out.MaxAllowed = in.MaxAllowed
+	return nil
+}
+
+func Convert_autoscaling_ContainerResourcePolicy_To_v1_ContainerResourcePolicy(in *autoscaling.ContainerResourcePolicy, out *ContainerResourcePolicy, s conversion.Scope) error {
+	out.ContainerName = in.ContainerName
+	if in.ControlledResources != nil {
+		in, out := &in.ControlledResources, &out.ControlledResources
+		*out = make([]api.ResourceName, len(*in))
+		copy(*out, *in)
+	} else {
+		out.ControlledResources = nil
+	}
+	out.MinAllowed = in.MinAllowed
+	out.MaxAllowed = in.MaxAllowed
+	return nil
+}
+
+func autoConvert_v1_VerticalPodAutoscalerStatus_To_autoscaling_VerticalPodAutoscalerStatus(in *VerticalPodAutoscalerStatus, out *autoscaling.VerticalPodAutoscalerStatus, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*VerticalPodAutoscalerStatus))(in)
+	}
+	if in.Recommendation != nil {
+		in, out := &in.Recommendation, &out.Recommendation
+		*out = new(autoscaling.RecommendedPodResources)
+		if err := Convert_v1_RecommendedPodResources_To_autoscaling_RecommendedPodResources(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Recommendation = nil
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]autoscaling.VerticalPodAutoscalerCondition, len(*in))
+		for i := range *in {
+			if err := Convert_v1_VerticalPodAutoscalerCondition_To_autoscaling_VerticalPodAutoscalerCondition(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Conditions = nil
+	}
+	return nil
+}
+
+func Convert_v1_VerticalPodAutoscalerStatus_To_autoscaling_VerticalPodAutoscalerStatus(in *VerticalPodAutoscalerStatus, out *autoscaling.VerticalPodAutoscalerStatus, s conversion.Scope) error {
+	return autoConvert_v1_VerticalPodAutoscalerStatus_To_autoscaling_VerticalPodAutoscalerStatus(in, out, s)
+}
+
+func autoConvert_autoscaling_VerticalPodAutoscalerStatus_To_v1_VerticalPodAutoscalerStatus(in *autoscaling.VerticalPodAutoscalerStatus, out *VerticalPodAutoscalerStatus, s conversion.Scope) error {
+	if defaulting, found := s.DefaultingInterface(reflect.TypeOf(*in)); found {
+		defaulting.(func(*autoscaling.VerticalPodAutoscalerStatus))(in)
+	}
+	if in.Recommendation != nil {
+		in, out := &in.Recommendation, &out.Recommendation
+		*out = new(RecommendedPodResources)
+		if err := Convert_autoscaling_RecommendedPodResources_To_v1_RecommendedPodResources(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Recommendation = nil
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VerticalPodAutoscalerCondition, len(*in))
+		for i := range *in {
+			if err := Convert_autoscaling_VerticalPodAutoscalerCondition_To_v1_VerticalPodAutoscalerCondition(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Conditions = nil
+	}
+	return nil
+}
+
+func Convert_autoscaling_VerticalPodAutoscalerStatus_To_v1_VerticalPodAutoscalerStatus(in *autoscaling.VerticalPodAutoscalerStatus, out *VerticalPodAutoscalerStatus, s conversion.Scope) error {
+	return autoConvert_autoscaling_VerticalPodAutoscalerStatus_To_v1_VerticalPodAutoscalerStatus(in, out, s)
+}
+
+func Convert_v1_RecommendedPodResources_To_autoscaling_RecommendedPodResources(in *RecommendedPodResources, out *autoscaling.RecommendedPodResources, s conversion.Scope) error {
+	if in.ContainerRecommendations != nil {
+		in, out := &in.ContainerRecommendations, &out.ContainerRecommendations
+		*out = make([]autoscaling.RecommendedContainerResources, len(*in))
+		for i := range *in {
+			if err := Convert_v1_RecommendedContainerResources_To_autoscaling_RecommendedContainerResources(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ContainerRecommendations = nil
+	}
+	return nil
+}
+
+func Convert_autoscaling_RecommendedPodResources_To_v1_RecommendedPodResources(in *autoscaling.RecommendedPodResources, out *RecommendedPodResources, s conversion.Scope) error {
+	if in.ContainerRecommendations != nil {
+		in, out := &in.ContainerRecommendations, &out.ContainerRecommendations
+		*out = make([]RecommendedContainerResources, len(*in))
+		for i := range *in {
+			if err := Convert_autoscaling_RecommendedContainerResources_To_v1_RecommendedContainerResources(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ContainerRecommendations = nil
+	}
+	return nil
+}
+
+func Convert_v1_RecommendedContainerResources_To_autoscaling_RecommendedContainerResources(in *RecommendedContainerResources, out *autoscaling.RecommendedContainerResources, s conversion.Scope) error {
+	out.ContainerName = in.ContainerName
+	out.Target = in.Target
+	out.LowerBound = in.LowerBound
+	out.UpperBound = in.UpperBound
+	return nil
+}
+
+func Convert_autoscaling_RecommendedContainerResources_To_v1_RecommendedContainerResources(in *autoscaling.RecommendedContainerResources, out *RecommendedContainerResources, s conversion.Scope) error {
+	out.ContainerName = in.ContainerName
+	out.Target = in.Target
+	out.LowerBound = in.LowerBound
+	out.UpperBound = in.UpperBound
+	return nil
+}
+
+func Convert_v1_VerticalPodAutoscalerCondition_To_autoscaling_VerticalPodAutoscalerCondition(in *VerticalPodAutoscalerCondition, out *autoscaling.VerticalPodAutoscalerCondition, s conversion.Scope) error {
+	out.Type = autoscaling.VerticalPodAutoscalerConditionType(in.Type)
+	out.Status = in.Status
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+func Convert_autoscaling_VerticalPodAutoscalerCondition_To_v1_VerticalPodAutoscalerCondition(in *autoscaling.VerticalPodAutoscalerCondition, out *VerticalPodAutoscalerCondition, s conversion.Scope) error {
+	out.Type = VerticalPodAutoscalerConditionType(in.Type)
+	out.Status = in.Status
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	api "k8s.io/kubernetes/pkg/api"
+	unversioned "k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// VerticalPodAutoscaler is the v1 wire format for the internal
+// autoscaling.VerticalPodAutoscaler type.
+type VerticalPodAutoscaler struct {
+	unversioned.TypeMeta `json:",inline"`
+	api.ObjectMeta       `json:"metadata,omitempty"`
+
+	Spec   VerticalPodAutoscalerSpec   `json:"spec,omitempty"`
+	Status VerticalPodAutoscalerStatus `json:"status,omitempty"`
+}
+
+// VerticalPodAutoscalerList is a list of VerticalPodAutoscalers.
+type VerticalPodAutoscalerList struct {
+	unversioned.TypeMeta `json:",inline"`
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	Items []VerticalPodAutoscaler `json:"items"`
+}
+
+// CrossVersionObjectReference identifies the controller a
+// VerticalPodAutoscaler or HorizontalPodAutoscaler targets; it is the same
+// type referenced by HorizontalPodAutoscalerSpec.ScaleTargetRef.
+type CrossVersionObjectReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// VerticalPodAutoscalerSpec is the v1 wire format for
+// autoscaling.VerticalPodAutoscalerSpec.
+type VerticalPodAutoscalerSpec struct {
+	TargetRef CrossVersionObjectReference `json:"targetRef"`
+
+	UpdatePolicy   PodUpdatePolicy   `json:"updatePolicy,omitempty"`
+	ResourcePolicy PodResourcePolicy `json:"resourcePolicy,omitempty"`
+}
+
+// UpdateMode describes how a VerticalPodAutoscaler applies its
+// recommendations to running pods. See the internal type for the meaning
+// of each value.
+type UpdateMode string
+
+const (
+	UpdateModeOff      UpdateMode = "Off"
+	UpdateModeInitial  UpdateMode = "Initial"
+	UpdateModeRecreate UpdateMode = "Recreate"
+	UpdateModeAuto     UpdateMode = "Auto"
+)
+
+// PodUpdatePolicy specifies how recommendations are applied to pods.
+type PodUpdatePolicy struct {
+	UpdateMode *UpdateMode `json:"updateMode,omitempty"`
+}
+
+// DefaultContainerResourcePolicyName is the special ContainerResourcePolicy
+// name that applies to every container not otherwise named in
+// PodResourcePolicy.ContainerPolicies.
+const DefaultContainerResourcePolicyName = "*"
+
+// PodResourcePolicy controls how the autoscaler computes recommended
+// resources for the containers of the target pods.
+type PodResourcePolicy struct {
+	ContainerPolicies []ContainerResourcePolicy `json:"containerPolicies,omitempty"`
+}
+
+// ContainerResourcePolicy constrains the recommendation for one container
+// (or, using DefaultContainerResourcePolicyName, every container without a
+// more specific entry).
+type ContainerResourcePolicy struct {
+	ContainerName string `json:"containerName,omitempty"`
+
+	ControlledResources []api.ResourceName `json:"controlledResources,omitempty"`
+
+	MinAllowed api.ResourceList `json:"minAllowed,omitempty"`
+	MaxAllowed api.ResourceList `json:"maxAllowed,omitempty"`
+}
+
+// VerticalPodAutoscalerStatus is the v1 wire format for
+// autoscaling.VerticalPodAutoscalerStatus.
+type VerticalPodAutoscalerStatus struct {
+	Recommendation *RecommendedPodResources         `json:"recommendation,omitempty"`
+	Conditions     []VerticalPodAutoscalerCondition `json:"conditions,omitempty"`
+}
+
+// RecommendedPodResources is the last computed recommendation, one entry
+// per observed container.
+type RecommendedPodResources struct {
+	ContainerRecommendations []RecommendedContainerResources `json:"containerRecommendations,omitempty"`
+}
+
+// RecommendedContainerResources carries the lower/target/upper bound
+// recommendation for a single container.
+type RecommendedContainerResources struct {
+	ContainerName string           `json:"containerName,omitempty"`
+	Target        api.ResourceList `json:"target"`
+	LowerBound    api.ResourceList `json:"lowerBound"`
+	UpperBound    api.ResourceList `json:"upperBound"`
+}
+
+// VerticalPodAutoscalerConditionType is a valid value for
+// VerticalPodAutoscalerCondition.Type.
+type VerticalPodAutoscalerConditionType string
+
+const (
+	ConfigUnsupported      VerticalPodAutoscalerConditionType = "ConfigUnsupported"
+	RecommendationProvided VerticalPodAutoscalerConditionType = "RecommendationProvided"
+)
+
+// VerticalPodAutoscalerCondition is a single condition on a
+// VerticalPodAutoscaler's status.
+type VerticalPodAutoscalerCondition struct {
+	Type    VerticalPodAutoscalerConditionType `json:"type"`
+	Status  api.ConditionStatus                `json:"status"`
+	Reason  string                             `json:"reason,omitempty"`
+	Message string                             `json:"message,omitempty"`
+}
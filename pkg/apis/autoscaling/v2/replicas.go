@@ -0,0 +1,48 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import "math"
+
+// DesiredReplicasForMetricRatios computes the desired replica count for a
+// HorizontalPodAutoscaler with one or more Metrics entries: for each metric
+// it scales currentReplicas by ratio (currentValue/targetValue, or
+// currentAverageValue/targetAverageValue for the Pods/average-value External
+// forms), rounds up, and the overall desired count is the maximum across all
+// of them. This is the formula the HPA controller applies once it has
+// resolved every MetricSpec to a current/target ratio; it lives here rather
+// than in the controller package so that the rounding rule stays in lockstep
+// with the API types it operates over.
+//
+// An empty metricRatios (e.g. all metrics failed to produce a reading this
+// tick) leaves currentReplicas unchanged rather than scaling to zero.
+func DesiredReplicasForMetricRatios(currentReplicas int32, metricRatios []float64) int32 {
+	if len(metricRatios) == 0 {
+		return currentReplicas
+	}
+	desired := replicasForRatio(currentReplicas, metricRatios[0])
+	for _, ratio := range metricRatios[1:] {
+		if rep := replicasForRatio(currentReplicas, ratio); rep > desired {
+			desired = rep
+		}
+	}
+	return desired
+}
+
+func replicasForRatio(currentReplicas int32, ratio float64) int32 {
+	return int32(math.Ceil(float64(currentReplicas) * ratio))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	api "k8s.io/kubernetes/pkg/api"
+	resource "k8s.io/kubernetes/pkg/api/resource"
+	unversioned "k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// HorizontalPodAutoscaler is the v2 configuration for a horizontal pod
+// autoscaler, which automatically manages the replica count of any resource
+// implementing the scale subresource based on the metrics specified.
+//
+// Unlike v1, which can only target CPU utilization, v2 lets Spec.Metrics
+// describe an arbitrary mix of resource, pod, object, and external metrics.
+type HorizontalPodAutoscaler struct {
+	unversioned.TypeMeta `json:",inline"`
+	api.ObjectMeta       `json:"metadata,omitempty"`
+
+	Spec   HorizontalPodAutoscalerSpec   `json:"spec,omitempty"`
+	Status HorizontalPodAutoscalerStatus `json:"status,omitempty"`
+}
+
+// HorizontalPodAutoscalerList is a list of HorizontalPodAutoscalers.
+type HorizontalPodAutoscalerList struct {
+	unversioned.TypeMeta `json:",inline"`
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	Items []HorizontalPodAutoscaler `json:"items"`
+}
+
+// CrossVersionObjectReference identifies another schema object, e.g. the
+// target of a scale action, by name and kind.
+type CrossVersionObjectReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// HorizontalPodAutoscalerSpec describes the desired scaling behavior for a
+// target resource.
+type HorizontalPodAutoscalerSpec struct {
+	ScaleTargetRef CrossVersionObjectReference `json:"scaleTargetRef"`
+
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32  `json:"maxReplicas"`
+
+	// Metrics is the set of metrics the autoscaler evaluates each tick; the
+	// desired replica count is the maximum of the per-metric recommendations.
+	Metrics []MetricSpec `json:"metrics,omitempty"`
+}
+
+// MetricSourceType indicates which field of a MetricSpec or MetricStatus is
+// populated.
+type MetricSourceType string
+
+const (
+	// ObjectMetricSourceType is a metric describing a single kubernetes
+	// object, such as the hit rate of an Ingress.
+	ObjectMetricSourceType MetricSourceType = "Object"
+	// PodsMetricSourceType is a metric describing each pod in the current
+	// scale target, averaged across them.
+	PodsMetricSourceType MetricSourceType = "Pods"
+	// ResourceMetricSourceType is a resource metric known to Kubernetes,
+	// as specified in requests and limits, describing each pod in the
+	// current scale target (e.g. CPU or memory).
+	ResourceMetricSourceType MetricSourceType = "Resource"
+	// ExternalMetricSourceType is a global metric not associated with any
+	// Kubernetes object, such as the length of a cloud provider queue.
+	ExternalMetricSourceType MetricSourceType = "External"
+)
+
+// MetricSpec specifies how to scale based on a single metric.
+type MetricSpec struct {
+	Type MetricSourceType `json:"type"`
+
+	Object   *ObjectMetricSource   `json:"object,omitempty"`
+	Pods     *PodsMetricSource     `json:"pods,omitempty"`
+	Resource *ResourceMetricSource `json:"resource,omitempty"`
+	External *ExternalMetricSource `json:"external,omitempty"`
+}
+
+// ObjectMetricSource targets a single in-cluster object by name.
+type ObjectMetricSource struct {
+	Target      CrossVersionObjectReference `json:"target"`
+	MetricName  string                      `json:"metricName"`
+	TargetValue resource.Quantity           `json:"targetValue"`
+}
+
+// PodsMetricSource targets a metric describing each pod in the current
+// scale target, averaged together and compared to TargetAverageValue.
+type PodsMetricSource struct {
+	MetricName         string            `json:"metricName"`
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
+// ResourceMetricSource targets a resource metric known to Kubernetes, as
+// specified in requests and limits, describing each pod in the current
+// scale target (e.g. CPU or memory). Only one of TargetAverageUtilization
+// and TargetAverageValue may be set.
+type ResourceMetricSource struct {
+	Name                     api.ResourceName   `json:"name"`
+	TargetAverageUtilization *int32             `json:"targetAverageUtilization,omitempty"`
+	TargetAverageValue       *resource.Quantity `json:"targetAverageValue,omitempty"`
+}
+
+// ExternalMetricSource targets a metric not associated with any Kubernetes
+// object, such as the length of a cloud provider queue. Only one of
+// TargetValue and TargetAverageValue may be set.
+type ExternalMetricSource struct {
+	MetricName         string                     `json:"metricName"`
+	MetricSelector     *unversioned.LabelSelector `json:"metricSelector,omitempty"`
+	TargetValue        *resource.Quantity         `json:"targetValue,omitempty"`
+	TargetAverageValue *resource.Quantity         `json:"targetAverageValue,omitempty"`
+}
+
+// HorizontalPodAutoscalerStatus describes the current status of a
+// HorizontalPodAutoscaler.
+type HorizontalPodAutoscalerStatus struct {
+	ObservedGeneration *int64          `json:"observedGeneration,omitempty"`
+	LastScaleTime      *unversioned.Time `json:"lastScaleTime,omitempty"`
+
+	CurrentReplicas int32 `json:"currentReplicas"`
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// CurrentMetrics is the last read state of each metric in Spec.Metrics,
+	// in the same order.
+	CurrentMetrics []MetricStatus `json:"currentMetrics"`
+}
+
+// MetricStatus is the last read state of a single metric.
+type MetricStatus struct {
+	Type MetricSourceType `json:"type"`
+
+	Object   *ObjectMetricStatus   `json:"object,omitempty"`
+	Pods     *PodsMetricStatus     `json:"pods,omitempty"`
+	Resource *ResourceMetricStatus `json:"resource,omitempty"`
+	External *ExternalMetricStatus `json:"external,omitempty"`
+}
+
+// ObjectMetricStatus is the last read state of an ObjectMetricSource.
+type ObjectMetricStatus struct {
+	Target       CrossVersionObjectReference `json:"target"`
+	MetricName   string                      `json:"metricName"`
+	CurrentValue resource.Quantity           `json:"currentValue"`
+}
+
+// PodsMetricStatus is the last read state of a PodsMetricSource.
+type PodsMetricStatus struct {
+	MetricName          string            `json:"metricName"`
+	CurrentAverageValue resource.Quantity `json:"currentAverageValue"`
+}
+
+// ResourceMetricStatus is the last read state of a ResourceMetricSource.
+type ResourceMetricStatus struct {
+	Name                      api.ResourceName  `json:"name"`
+	CurrentAverageUtilization *int32            `json:"currentAverageUtilization,omitempty"`
+	CurrentAverageValue       resource.Quantity `json:"currentAverageValue"`
+}
+
+// ExternalMetricStatus is the last read state of an ExternalMetricSource.
+type ExternalMetricStatus struct {
+	MetricName         string                     `json:"metricName"`
+	MetricSelector     *unversioned.LabelSelector `json:"metricSelector,omitempty"`
+	CurrentValue       resource.Quantity          `json:"currentValue"`
+	CurrentAverageValue *resource.Quantity        `json:"currentAverageValue,omitempty"`
+}
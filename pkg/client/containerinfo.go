@@ -0,0 +1,112 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/google/cadvisor/info"
+)
+
+const (
+	statsURI       = "/stats"
+	machineSpecURI = "/spec"
+)
+
+// ContainerInfoGetter knows how to fetch cadvisor container and machine
+// stats from a kubelet. The VPA Recommender polls GetContainerInfo to build
+// its per-container usage histograms.
+type ContainerInfoGetter interface {
+	GetContainerInfo(host, podID, containerID string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
+	GetMachineInfo(host string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
+	GetMachineSpec(host string) (*info.MachineInfo, error)
+}
+
+// HTTPContainerInfoGetter is the straightforward ContainerInfoGetter that
+// talks to the cadvisor HTTP endpoint a kubelet exposes on Port.
+type HTTPContainerInfoGetter struct {
+	Client *http.Client
+	Port   int
+}
+
+// GetContainerInfo fetches cadvisor stats for one container of one pod on
+// host.
+func (self *HTTPContainerInfoGetter) GetContainerInfo(host, podID, containerID string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	return self.getContainerInfo(host, path.Join(statsURI, podID, containerID), req)
+}
+
+// GetMachineInfo fetches cadvisor's root-container stats for host, which
+// approximate whole-machine usage.
+func (self *HTTPContainerInfoGetter) GetMachineInfo(host string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	return self.getContainerInfo(host, statsURI, req)
+}
+
+func (self *HTTPContainerInfoGetter) getContainerInfo(host, uri string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(req.FillDefaults()); err != nil {
+		return nil, err
+	}
+
+	u := self.hostURL(host, uri)
+	resp, err := self.Client.Post(u, "application/json", &body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %q failed with status %d", u, resp.StatusCode)
+	}
+
+	var cinfo info.ContainerInfo
+	if err := json.NewDecoder(resp.Body).Decode(&cinfo); err != nil {
+		return nil, err
+	}
+	return &cinfo, nil
+}
+
+// GetMachineSpec fetches the static cadvisor MachineInfo (cores, memory
+// capacity) for host.
+func (self *HTTPContainerInfoGetter) GetMachineSpec(host string) (*info.MachineInfo, error) {
+	u := self.hostURL(host, machineSpecURI)
+	resp, err := self.Client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %q failed with status %d", u, resp.StatusCode)
+	}
+
+	var minfo info.MachineInfo
+	if err := json.NewDecoder(resp.Body).Decode(&minfo); err != nil {
+		return nil, err
+	}
+	return &minfo, nil
+}
+
+func (self *HTTPContainerInfoGetter) hostURL(host, uri string) string {
+	return (&url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s:%d", host, self.Port),
+		Path:   uri,
+	}).String()
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/google/cadvisor/info"
+
+	api "k8s.io/kubernetes/pkg/api"
+	unversioned "k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+const (
+	nodeMetricsPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+	podMetricsPath  = "/apis/metrics.k8s.io/v1beta1/namespaces"
+)
+
+// ContainerMetrics is the aggregated metrics.k8s.io/v1beta1 wire format for
+// a single container's point-in-time resource usage.
+type ContainerMetrics struct {
+	Name  string           `json:"name"`
+	Usage api.ResourceList `json:"usage"`
+}
+
+// PodMetrics is the aggregated metrics.k8s.io/v1beta1 wire format for a
+// single pod's point-in-time resource usage.
+type PodMetrics struct {
+	unversioned.TypeMeta `json:",inline"`
+	api.ObjectMeta       `json:"metadata,omitempty"`
+
+	Timestamp  unversioned.Time     `json:"timestamp"`
+	Window     unversioned.Duration `json:"window"`
+	Containers []ContainerMetrics   `json:"containers"`
+}
+
+// PodMetricsList is a list of PodMetrics.
+type PodMetricsList struct {
+	unversioned.TypeMeta `json:",inline"`
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	Items []PodMetrics `json:"items"`
+}
+
+// MetricsClient is a ContainerInfoGetter that serves GetContainerInfo from
+// the aggregated metrics.k8s.io/v1beta1 API (as exposed by metrics-server)
+// instead of scraping each kubelet's cadvisor endpoint directly, so the
+// Recommender doesn't need direct network access to every node.
+// GetMachineInfo and GetMachineSpec have no metrics.k8s.io equivalent, so
+// they're always served by Fallback.
+//
+// metrics-server only keeps the latest sample per pod, unlike cadvisor's
+// rolling window, so a single poll only ever yields one ContainerStats; the
+// Recommender's histograms accumulate the series across repeated polls.
+type MetricsClient struct {
+	Client  *http.Client
+	Host    string // host:port of the aggregated API server, reached over https
+	Fallback ContainerInfoGetter
+}
+
+// GetContainerInfo fetches the latest metrics.k8s.io sample for a single
+// container of a pod, translating it into an info.ContainerInfo with one
+// ContainerStats entry. If the aggregated API is unavailable it falls back
+// to m.Fallback, which is expected to scrape the kubelet on host directly.
+func (m *MetricsClient) GetContainerInfo(host, podID, containerID string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	pod, err := m.getPodMetrics(podID)
+	if err != nil {
+		if isNotFoundOrUnavailable(err) && m.Fallback != nil {
+			return m.Fallback.GetContainerInfo(host, podID, containerID, req)
+		}
+		return nil, err
+	}
+	for _, c := range pod.Containers {
+		if c.Name == containerID {
+			return containerInfoFromMetrics(podID, pod.Timestamp.Time, c.Usage), nil
+		}
+	}
+	return nil, fmt.Errorf("no container named %q in PodMetrics for pod %q", containerID, podID)
+}
+
+// GetMachineInfo has no aggregated-metrics equivalent (metrics.k8s.io only
+// reports pod and node totals, not a machine's root-container stats), so it
+// always defers to m.Fallback.
+func (m *MetricsClient) GetMachineInfo(host string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	if m.Fallback == nil {
+		return nil, fmt.Errorf("metrics.k8s.io has no machine-info equivalent and no fallback ContainerInfoGetter was configured")
+	}
+	return m.Fallback.GetMachineInfo(host, req)
+}
+
+// GetMachineSpec always defers to m.Fallback, since metrics.k8s.io doesn't
+// expose static machine capacity.
+func (m *MetricsClient) GetMachineSpec(host string) (*info.MachineInfo, error) {
+	if m.Fallback == nil {
+		return nil, fmt.Errorf("metrics.k8s.io has no machine-spec equivalent and no fallback ContainerInfoGetter was configured")
+	}
+	return m.Fallback.GetMachineSpec(host)
+}
+
+// getPodMetrics looks up podID (namespace/name) via the aggregated API.
+// podID is expected in "namespace/name" form, matching how the rest of this
+// package's callers already key containers by pod.
+func (m *MetricsClient) getPodMetrics(podID string) (*PodMetrics, error) {
+	namespace, name := splitPodID(podID)
+
+	u := (&url.URL{Scheme: "https", Host: m.Host, Path: path.Join(podMetricsPath, namespace, "pods", name)}).String()
+	resp, err := m.Client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusServiceUnavailable {
+		return nil, &metricsUnavailableError{status: resp.StatusCode}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %q failed with status %d", u, resp.StatusCode)
+	}
+
+	var pod PodMetrics
+	if err := json.NewDecoder(resp.Body).Decode(&pod); err != nil {
+		return nil, err
+	}
+	return &pod, nil
+}
+
+// splitPodID splits a "namespace/name" podID; a podID with no slash is
+// treated as a bare name in the default namespace, matching how
+// HTTPContainerInfoGetter's tests exercise this package with unqualified
+// pod IDs.
+func splitPodID(podID string) (namespace, name string) {
+	for i := 0; i < len(podID); i++ {
+		if podID[i] == '/' {
+			return podID[:i], podID[i+1:]
+		}
+	}
+	return api.NamespaceDefault, podID
+}
+
+func containerInfoFromMetrics(podID string, at time.Time, usage api.ResourceList) *info.ContainerInfo {
+	stat := &info.ContainerStats{Timestamp: at}
+	if cpu, ok := usage[api.ResourceCPU]; ok {
+		stat.Cpu.Usage.Total = uint64(cpu.MilliValue()) * uint64(time.Millisecond/time.Nanosecond)
+	}
+	if mem, ok := usage[api.ResourceMemory]; ok {
+		stat.Memory.Usage = uint64(mem.Value())
+	}
+	return &info.ContainerInfo{
+		ContainerReference: info.ContainerReference{Name: podID},
+		Stats:              []*info.ContainerStats{stat},
+	}
+}
+
+type metricsUnavailableError struct {
+	status int
+}
+
+func (e *metricsUnavailableError) Error() string {
+	return fmt.Sprintf("metrics.k8s.io API unavailable: status %d", e.status)
+}
+
+func isNotFoundOrUnavailable(err error) bool {
+	_, ok := err.(*metricsUnavailableError)
+	return ok
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/cadvisor/info"
+
+	api "k8s.io/kubernetes/pkg/api"
+	resource "k8s.io/kubernetes/pkg/api/resource"
+)
+
+func TestMetricsClientGetContainerInfoSuccessfully(t *testing.T) {
+	pod := PodMetrics{
+		Containers: []ContainerMetrics{
+			{
+				Name: "containerNameInK8S",
+				Usage: api.ResourceList{
+					api.ResourceCPU:    resource.MustParse("250m"),
+					api.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			},
+		},
+	}
+	expectedPath := "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods/somePodID"
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimRight(r.URL.Path, "/") != expectedPath {
+			t.Fatalf("received request to an invalid path; should be %v, got %v", expectedPath, r.URL.Path)
+		}
+		if err := json.NewEncoder(w).Encode(pod); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer ts.Close()
+
+	hostURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &MetricsClient{Client: ts.Client(), Host: hostURL.Host}
+	cinfo, err := client.GetContainerInfo("ignored-host", "somePodID", "containerNameInK8S", nil)
+	if err != nil {
+		t.Fatalf("received unexpected error: %v", err)
+	}
+	if len(cinfo.Stats) != 1 {
+		t.Fatalf("expected exactly one ContainerStats sample, got %d", len(cinfo.Stats))
+	}
+	if got, want := cinfo.Stats[0].Memory.Usage, uint64(128*1024*1024); got != want {
+		t.Errorf("Memory.Usage = %d, want %d", got, want)
+	}
+	if got, want := cinfo.Stats[0].Cpu.Usage.Total, uint64(250000000); got != want {
+		t.Errorf("Cpu.Usage.Total = %d, want %d", got, want)
+	}
+}
+
+func TestMetricsClientFallsBackOnNotFound(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	hostURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fallbackCalled := false
+	client := &MetricsClient{
+		Client: ts.Client(),
+		Host:   hostURL.Host,
+		Fallback: fallbackContainerInfoGetterFunc(func(host, podID, containerID string) {
+			fallbackCalled = true
+		}),
+	}
+	if _, err := client.GetContainerInfo("ignored-host", "somePodID", "containerNameInK8S", nil); err != nil {
+		t.Fatalf("received unexpected error: %v", err)
+	}
+	if !fallbackCalled {
+		t.Error("expected Fallback to be used after a NotFound response")
+	}
+}
+
+// fallbackContainerInfoGetterFunc is a minimal ContainerInfoGetter stub
+// used to assert that MetricsClient defers to its Fallback.
+type fallbackContainerInfoGetterFunc func(host, podID, containerID string)
+
+func (f fallbackContainerInfoGetterFunc) GetContainerInfo(host, podID, containerID string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	f(host, podID, containerID)
+	return &info.ContainerInfo{}, nil
+}
+
+func (f fallbackContainerInfoGetterFunc) GetMachineInfo(host string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	return &info.ContainerInfo{}, nil
+}
+
+func (f fallbackContainerInfoGetterFunc) GetMachineSpec(host string) (*info.MachineInfo, error) {
+	return &info.MachineInfo{}, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/kubernetes/pkg/cloudprovider"
+	"k8s.io/kubernetes/pkg/types"
+)
+
+// diskLunReservations tracks LUNs that have been handed out by
+// ReserveDiskLun but aren't confirmed in the VM's own DataDisks yet, so
+// two concurrent AttachDisk calls to the same VM cannot pick the same LUN
+// before either ARM update lands.
+var diskLunReservations = struct {
+	mu       sync.Mutex
+	reserved map[types.NodeName]map[int32]string // nodeName -> lun -> diskName
+}{
+	reserved: make(map[types.NodeName]map[int32]string),
+}
+
+// ReserveDiskLun atomically picks the next free LUN on nodeName for
+// diskName and reserves it against both the VM's current DataDisks and
+// any other reservation already outstanding for that node. Release the
+// reservation via releaseDiskLun once the attach is confirmed (the disk
+// now appears in the VM's own DataDisks, making the reservation
+// redundant) or on detach.
+func (az *Cloud) ReserveDiskLun(nodeName types.NodeName, diskName string) (int32, error) {
+	diskLunReservations.mu.Lock()
+	defer diskLunReservations.mu.Unlock()
+
+	vm, exists, err := az.getVirtualMachine(nodeName)
+	if err != nil {
+		return -1, err
+	} else if !exists {
+		return -1, cloudprovider.InstanceNotFound
+	}
+
+	used := make([]bool, maxLUN)
+	disks := *vm.Properties.StorageProfile.DataDisks
+	for _, disk := range disks {
+		if disk.Lun != nil {
+			used[*disk.Lun] = true
+		}
+	}
+	for lun := range diskLunReservations.reserved[nodeName] {
+		used[lun] = true
+	}
+
+	for lun, taken := range used {
+		if taken {
+			continue
+		}
+		if diskLunReservations.reserved[nodeName] == nil {
+			diskLunReservations.reserved[nodeName] = make(map[int32]string)
+		}
+		diskLunReservations.reserved[nodeName][int32(lun)] = diskName
+		return int32(lun), nil
+	}
+	return -1, fmt.Errorf("All Luns are used")
+}
+
+// releaseDiskLun releases any LUN reservation held for diskName on
+// nodeName.
+func (az *Cloud) releaseDiskLun(nodeName types.NodeName, diskName string) {
+	diskLunReservations.mu.Lock()
+	defer diskLunReservations.mu.Unlock()
+
+	for lun, name := range diskLunReservations.reserved[nodeName] {
+		if name == diskName {
+			delete(diskLunReservations.reserved[nodeName], lun)
+		}
+	}
+}
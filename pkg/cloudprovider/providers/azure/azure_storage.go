@@ -18,7 +18,11 @@ package azure
 
 import (
 	"fmt"
+	"hash/fnv"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/arm/compute"
 	"github.com/golang/glog"
@@ -28,11 +32,73 @@ import (
 
 const (
 	maxLUN = 64 // max number of LUNs per VM
+
+	// storageAccountTypeShared and storageAccountTypeDedicated both
+	// provision a VHD page blob, in the cluster's shared storage account
+	// or in an account dedicated to the volume, respectively.
+	// storageAccountTypeManaged provisions an ARM managed disk instead,
+	// with no storage account involved at all.
+	storageAccountTypeShared    = "shared"
+	storageAccountTypeDedicated = "dedicated"
+	storageAccountTypeManaged   = "managed"
+
+	// defaultMaxStorageAccounts bounds how many dedicated accounts
+	// CreateVolume will auto-provision before giving up, so a
+	// persistently "full" set of accounts can't grow the subscription's
+	// account count without bound. Overridable via
+	// az.Config.MaxStorageAccounts.
+	defaultMaxStorageAccounts = 100
+
+	// defaultMaxVHDsPerStorageAccount caps how many VHDs CreateVolume
+	// will place in one auto-managed account before rolling to a new
+	// one. Azure recommends no more than ~40 standard-tier disks per
+	// account for IOPS headroom. Overridable via
+	// az.Config.MaxVHDsPerStorageAccount.
+	defaultMaxVHDsPerStorageAccount = 40
 )
 
-// AttachDisk attaches a vhd to vm
-// the vhd must exist, can be identified by diskName, diskURI, and lun.
+// AttachDisk attaches a vhd or managed disk to vm.
+// The disk must exist, and can be identified by diskName, diskURI, and lun.
+// When az.vmDiskBatcher is set, the actual VM update is serialized per-node
+// and may be batched with other pending attach/detach calls for the same
+// node; until every caller constructs one, az.vmDiskBatcher may be nil, in
+// which case AttachDisk falls back to its own unbatched read-modify-write.
 func (az *Cloud) AttachDisk(diskName, diskURI string, nodeName types.NodeName, lun int32, cachingMode compute.CachingTypes) error {
+	var err error
+	if az.vmDiskBatcher != nil {
+		req := &vmDiskRequest{
+			op:          vmDiskAttach,
+			diskName:    diskName,
+			diskURI:     diskURI,
+			lun:         lun,
+			cachingMode: cachingMode,
+			result:      make(chan error, 1),
+		}
+		err = az.vmDiskBatcher.enqueue(nodeName, req)
+	} else {
+		err = az.attachDiskInline(diskName, diskURI, nodeName, lun, cachingMode)
+	}
+	if err != nil {
+		glog.Errorf("azure attach failed, err: %v", err)
+		if strings.Contains(err.Error(), "Code=\"AcquireDiskLeaseFailed\"") {
+			// if lease cannot be acquired, immediately detach the disk and return the original error
+			glog.Infof("failed to acquire disk lease, try detach")
+			az.DetachDiskByName(diskName, diskURI, nodeName)
+		}
+	} else {
+		glog.V(4).Infof("azure attach succeeded")
+	}
+	// the disk is now confirmed in the VM's own DataDisks (or attach
+	// failed outright), so any ReserveDiskLun reservation for it is no
+	// longer needed either way.
+	az.releaseDiskLun(nodeName, diskName)
+	return err
+}
+
+// attachDiskInline is the unbatched fallback for AttachDisk: it reads the
+// VM, appends the new data disk, and writes it back with a single
+// CreateOrUpdate of its own.
+func (az *Cloud) attachDiskInline(diskName, diskURI string, nodeName types.NodeName, lun int32, cachingMode compute.CachingTypes) error {
 	vm, exists, err := az.getVirtualMachine(nodeName)
 	if err != nil {
 		return err
@@ -40,16 +106,7 @@ func (az *Cloud) AttachDisk(diskName, diskURI string, nodeName types.NodeName, l
 		return cloudprovider.InstanceNotFound
 	}
 	disks := *vm.Properties.StorageProfile.DataDisks
-	disks = append(disks,
-		compute.DataDisk{
-			Name: &diskName,
-			Vhd: &compute.VirtualHardDisk{
-				URI: &diskURI,
-			},
-			Lun:          &lun,
-			Caching:      cachingMode,
-			CreateOption: "attach",
-		})
+	disks = append(disks, newDataDisk(diskName, diskURI, lun, cachingMode))
 
 	newVM := compute.VirtualMachine{
 		Location: vm.Location,
@@ -61,22 +118,13 @@ func (az *Cloud) AttachDisk(diskName, diskURI string, nodeName types.NodeName, l
 	}
 	vmName := mapNodeNameToVMName(nodeName)
 	_, err = az.VirtualMachinesClient.CreateOrUpdate(az.ResourceGroup, vmName, newVM, nil)
-	if err != nil {
-		glog.Errorf("azure attach failed, err: %v", err)
-		detail := err.Error()
-		if strings.Contains(detail, "Code=\"AcquireDiskLeaseFailed\"") {
-			// if lease cannot be acquired, immediately detach the disk and return the original error
-			glog.Infof("failed to acquire disk lease, try detach")
-			az.DetachDiskByName(diskName, diskURI, nodeName)
-		}
-	} else {
-		glog.V(4).Infof("azure attach succeeded")
-	}
 	return err
 }
 
 // DetachDiskByName detaches a vhd from host
-// the vhd can be identified by diskName or diskURI
+// the vhd can be identified by diskName or diskURI. Like AttachDisk, the VM
+// update goes through az.vmDiskBatcher when one is set, and falls back to
+// an unbatched read-modify-write otherwise.
 func (az *Cloud) DetachDiskByName(diskName, diskURI string, nodeName types.NodeName) error {
 	vm, exists, err := az.getVirtualMachine(nodeName)
 	if err != nil || !exists {
@@ -85,15 +133,32 @@ func (az *Cloud) DetachDiskByName(diskName, diskURI string, nodeName types.NodeN
 		return nil
 	}
 
-	disks := *vm.Properties.StorageProfile.DataDisks
-	for i, disk := range disks {
-		if (disk.Name != nil && diskName != "" && *disk.Name == diskName) || (disk.Vhd.URI != nil && diskURI != "" && *disk.Vhd.URI == diskURI) {
-			// found the disk
-			glog.V(4).Infof("detach disk: name %q uri %q", diskName, diskURI)
-			disks = append(disks[:i], disks[i+1:]...)
-			break
+	if az.vmDiskBatcher != nil {
+		req := &vmDiskRequest{
+			op:       vmDiskDetach,
+			diskName: diskName,
+			diskURI:  diskURI,
+			result:   make(chan error, 1),
 		}
+		err = az.vmDiskBatcher.enqueue(nodeName, req)
+	} else {
+		err = az.detachDiskByNameInline(vm, diskName, diskURI, nodeName)
 	}
+	if err != nil {
+		glog.Errorf("azure disk detach failed, err: %v", err)
+	} else {
+		glog.V(4).Infof("azure disk detach succeeded")
+	}
+	az.releaseDiskLun(nodeName, diskName)
+	return err
+}
+
+// detachDiskByNameInline is the unbatched fallback for DetachDiskByName: it
+// removes the matching data disk from the already-fetched vm and writes
+// the result back with a single CreateOrUpdate of its own.
+func (az *Cloud) detachDiskByNameInline(vm compute.VirtualMachine, diskName, diskURI string, nodeName types.NodeName) error {
+	disks := removeDataDisk(*vm.Properties.StorageProfile.DataDisks, diskName, diskURI)
+
 	newVM := compute.VirtualMachine{
 		Location: vm.Location,
 		Properties: &compute.VirtualMachineProperties{
@@ -103,16 +168,12 @@ func (az *Cloud) DetachDiskByName(diskName, diskURI string, nodeName types.NodeN
 		},
 	}
 	vmName := mapNodeNameToVMName(nodeName)
-	_, err = az.VirtualMachinesClient.CreateOrUpdate(az.ResourceGroup, vmName, newVM, nil)
-	if err != nil {
-		glog.Errorf("azure disk detach failed, err: %v", err)
-	} else {
-		glog.V(4).Infof("azure disk detach succeeded")
-	}
+	_, err := az.VirtualMachinesClient.CreateOrUpdate(az.ResourceGroup, vmName, newVM, nil)
 	return err
 }
 
-// GetDiskLun finds the lun on the host that the vhd is attached to, given a vhd's diskName and diskURI
+// GetDiskLun finds the lun on the host that the vhd or managed disk is
+// attached to, given its diskName and diskURI.
 func (az *Cloud) GetDiskLun(diskName, diskURI string, nodeName types.NodeName) (int32, error) {
 	vm, exists, err := az.getVirtualMachine(nodeName)
 	if err != nil {
@@ -122,7 +183,7 @@ func (az *Cloud) GetDiskLun(diskName, diskURI string, nodeName types.NodeName) (
 	}
 	disks := *vm.Properties.StorageProfile.DataDisks
 	for _, disk := range disks {
-		if disk.Lun != nil && (disk.Name != nil && diskName != "" && *disk.Name == diskName) || (disk.Vhd.URI != nil && diskURI != "" && *disk.Vhd.URI == diskURI) {
+		if disk.Lun != nil && dataDiskMatches(disk, diskName, diskURI) {
 			// found the disk
 			glog.V(4).Infof("find disk: lun %d name %q uri %q", *disk.Lun, diskName, diskURI)
 			return *disk.Lun, nil
@@ -131,41 +192,34 @@ func (az *Cloud) GetDiskLun(diskName, diskURI string, nodeName types.NodeName) (
 	return -1, fmt.Errorf("Cannot find Lun for disk %s", diskName)
 }
 
-// GetNextDiskLun searches all vhd attachment on the host and find unused lun
-// return -1 if all luns are used
-func (az *Cloud) GetNextDiskLun(nodeName types.NodeName) (int32, error) {
-	vm, exists, err := az.getVirtualMachine(nodeName)
-	if err != nil {
-		return -1, err
-	} else if !exists {
-		return -1, cloudprovider.InstanceNotFound
-	}
-	used := make([]bool, maxLUN)
-	disks := *vm.Properties.StorageProfile.DataDisks
-	for _, disk := range disks {
-		if disk.Lun != nil {
-			used[*disk.Lun] = true
-		}
-	}
-	for k, v := range used {
-		if !v {
-			return int32(k), nil
-		}
-	}
-	return -1, fmt.Errorf("All Luns are used")
+// GetNextDiskLun searches all vhd attachment on the host and find unused lun,
+// reserving it for diskName so a concurrent call for a different disk can't
+// pick the same lun before either attach lands. Release the reservation via
+// releaseDiskLun once the attach is confirmed or abandoned. This is now a
+// thin wrapper around ReserveDiskLun, which does the actual VM read and
+// reservation bookkeeping; return -1 if all luns are used.
+func (az *Cloud) GetNextDiskLun(nodeName types.NodeName, diskName string) (int32, error) {
+	return az.ReserveDiskLun(nodeName, diskName)
 }
 
-// CreateVolume creates a VHD blob in a storage account that has storageType and location
-func (az *Cloud) CreateVolume(name, storageType, location string, requestGB int) (string, string, int, error) {
+// CreateVolume creates a disk of the given kind ("shared", "dedicated" or
+// "managed"; "" defaults to "shared") with storageType and location. For
+// "shared"/"dedicated" it creates a VHD page blob; for "managed" it
+// creates an ARM managed disk instead, and storageType is interpreted as
+// a compute.StorageAccountTypes value (Standard_LRS, Premium_LRS, ...).
+func (az *Cloud) CreateVolume(name, storageType, location string, requestGB int, kind string) (string, string, int, error) {
+	if kind == storageAccountTypeManaged {
+		return az.createManagedDisk(name, storageType, location, requestGB)
+	}
+
 	// find a storage account
 	accounts, err := az.getStorageAccounts()
 	if err != nil {
-		// TODO: create a storage account and container
 		return "", "", 0, err
 	}
 	for _, account := range accounts {
 		glog.V(4).Infof("account %s type %s location %s", account.Name, account.StorageType, account.Location)
-		if (storageType == "" || account.StorageType == storageType) && (location == "" || account.Location == location) {
+		if kind != storageAccountTypeDedicated && (storageType == "" || account.StorageType == storageType) && (location == "" || account.Location == location) {
 			// find the access key with this account
 			key, err := az.getStorageAccesskey(account.Name)
 			if err != nil {
@@ -173,6 +227,16 @@ func (az *Cloud) CreateVolume(name, storageType, location string, requestGB int)
 				continue
 			}
 
+			full, err := az.vhdAccountIsFull(account.Name, key)
+			if err != nil {
+				glog.V(2).Infof("could not determine vhd count for account %s: %v", account.Name, err)
+				continue
+			}
+			if full {
+				glog.V(4).Infof("account %s has reached its VHD limit, skipping", account.Name)
+				continue
+			}
+
 			// create a page blob in this account's vhd container
 			name, uri, err := az.createVhdBlob(account.Name, key, name, int64(requestGB), nil)
 			if err != nil {
@@ -183,12 +247,128 @@ func (az *Cloud) CreateVolume(name, storageType, location string, requestGB int)
 			return name, uri, requestGB, err
 		}
 	}
-	return "", "", 0, fmt.Errorf("failed to find a matching storage account")
+
+	// kind is "dedicated", or no existing account matched (or all matching
+	// accounts are full): auto-provision a new dedicated account for this
+	// volume.
+	account, key, err := az.createStorageAccountForNewVHD(storageType, location)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to find a matching storage account and failed to create a new one: %v", err)
+	}
+	name, uri, err := az.createVhdBlob(account, key, name, int64(requestGB), nil)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("created storage account %s but failed to create vhd in it: %v", account, err)
+	}
+	glog.V(4).Infof("created vhd blob uri: %s in newly provisioned account %s", uri, account)
+	return name, uri, requestGB, nil
+}
+
+// vhdAccountIsFull reports whether account already holds at least
+// az.vhdCountThreshold() VHDs. Azure recommends no more than ~40
+// standard-tier disks per account for IOPS headroom, so CreateVolume rolls
+// to a new account rather than keep stacking VHDs onto one that's full.
+func (az *Cloud) vhdAccountIsFull(accountName, accountKey string) (bool, error) {
+	blobs, err := az.listVhdBlobs(accountName, accountKey)
+	if err != nil {
+		return false, err
+	}
+	return len(blobs) >= az.vhdCountThreshold(), nil
+}
+
+// vhdCountThreshold returns the configured per-account VHD cap, falling
+// back to defaultMaxVHDsPerStorageAccount when the cloud config leaves it
+// unset (0).
+func (az *Cloud) vhdCountThreshold() int {
+	if az.Config.MaxVHDsPerStorageAccount > 0 {
+		return az.Config.MaxVHDsPerStorageAccount
+	}
+	return defaultMaxVHDsPerStorageAccount
+}
+
+// createStorageAccountForNewVHD auto-provisions a dedicated storage
+// account for a single new VHD: it creates the account, waits for it to
+// finish provisioning, then creates the account's vhds container. It
+// refuses once az.Config.MaxStorageAccounts auto-managed accounts already
+// exist, so a persistently "full" set of accounts can't grow the
+// subscription's account count without bound.
+func (az *Cloud) createStorageAccountForNewVHD(storageType, location string) (accountName, accountKey string, err error) {
+	accounts, err := az.getStorageAccounts()
+	if err != nil {
+		return "", "", err
+	}
+	maxAccounts := az.Config.MaxStorageAccounts
+	if maxAccounts <= 0 {
+		maxAccounts = defaultMaxStorageAccounts
+	}
+	if len(accounts) >= maxAccounts {
+		return "", "", fmt.Errorf("already have %d storage accounts, at the configured maximum of %d", len(accounts), maxAccounts)
+	}
+
+	accountName = generateStorageAccountName()
+	glog.V(2).Infof("auto-provisioning dedicated storage account %s (type %s, location %s)", accountName, storageType, location)
+
+	if err := az.createStorageAccount(accountName, storageType, location); err != nil {
+		return "", "", fmt.Errorf("failed to create storage account %s: %v", accountName, err)
+	}
+	if err := az.waitStorageAccountProvisioned(accountName); err != nil {
+		return "", "", fmt.Errorf("storage account %s did not finish provisioning: %v", accountName, err)
+	}
+
+	accountKey, err = az.getStorageAccesskey(accountName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get access key for new storage account %s: %v", accountName, err)
+	}
+	if err := az.createVhdContainer(accountName, accountKey); err != nil {
+		return "", "", fmt.Errorf("failed to create vhds container in new storage account %s: %v", accountName, err)
+	}
+
+	return accountName, accountKey, nil
+}
+
+// generateStorageAccountName returns a name of the form "k8s<hash>",
+// matching the naming Azure storage accounts require: 3-24 characters,
+// lowercase letters and digits only.
+func generateStorageAccountName() string {
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%d", time.Now().UnixNano())))
+	return fmt.Sprintf("k8s%x", h.Sum32())
+}
+
+// createManagedDisk creates an ARM managed disk resource named name and
+// returns its name, its full ARM resource ID (the form AttachDisk and
+// GetDiskLun expect for ManagedDisk.ID), and its size.
+func (az *Cloud) createManagedDisk(name, storageType, location string, requestGB int) (string, string, int, error) {
+	diskSizeGB := int32(requestGB)
+	model := compute.Disk{
+		Name:     &name,
+		Location: &location,
+		Properties: &compute.DiskProperties{
+			CreationData: &compute.CreationData{CreateOption: compute.Empty},
+			DiskSizeGB:   &diskSizeGB,
+		},
+		Sku: &compute.DiskSku{
+			Name: compute.StorageAccountTypes(storageType),
+		},
+	}
+
+	if _, err := az.DisksClient.CreateOrUpdate(az.ResourceGroup, name, model, nil); err != nil {
+		return "", "", 0, fmt.Errorf("failed to create managed disk %s: %v", name, err)
+	}
+
+	diskID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s",
+		az.SubscriptionID, az.ResourceGroup, name)
+	glog.V(4).Infof("created managed disk: %s", diskID)
+	return name, diskID, requestGB, nil
 }
 
-// DeleteVolume deletes a VHD blob
+// DeleteVolume deletes a VHD blob or managed disk, identified by uri as
+// returned from CreateVolume.
 func (az *Cloud) DeleteVolume(name, uri string) error {
-	accountName, blob, err := az.getBlobNameAndAccountFromURI(uri)
+	if isManagedDiskURI(uri) {
+		return az.deleteManagedDisk(name)
+	}
+
+	meta, err := parseUnmanagedDiskURI(uri)
 	if err != nil {
 		return fmt.Errorf("failed to parse vhd URI %v", err)
 	}
@@ -199,14 +379,14 @@ func (az *Cloud) DeleteVolume(name, uri string) error {
 		return err
 	}
 	for _, account := range accounts {
-		if accountName == account.Name {
+		if meta.StorageAccountName == account.Name {
 			key, err := az.getStorageAccesskey(account.Name)
 			if err != nil {
 				glog.Warningf("no key for storage account %s", account.Name)
 				continue
 			}
 
-			err = az.deleteVhdBlob(account.Name, key, blob)
+			err = az.deleteVhdBlob(account.Name, key, meta.BlobName)
 			if err != nil {
 				glog.Warningf("failed to delete blob %s err: %v", uri, err)
 				continue
@@ -215,5 +395,179 @@ func (az *Cloud) DeleteVolume(name, uri string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("failed to find storage account for vhd %v, account %s, blob %s", uri, accountName, blob)
+	return fmt.Errorf("failed to find storage account for vhd %v, account %s, blob %s", uri, meta.StorageAccountName, meta.BlobName)
+}
+
+// UnmanagedDiskMetadata is the parsed form of an unmanaged VHD blob URI
+// (https://<account>.blob.<suffix>/<container>/<blob>), plus the resource
+// group the storage account actually lives in. Unlike the account,
+// container and blob names, the resource group isn't encoded anywhere in
+// the URI itself, so resolving it requires an extra subscription-wide
+// lookup -- see storageAccountResourceGroup.
+type UnmanagedDiskMetadata struct {
+	StorageAccountName string
+	ContainerName      string
+	BlobName           string
+	ResourceGroup      string
+}
+
+// parseUnmanagedDiskURI validates and parses an unmanaged VHD blob URI of
+// the form https://<account>.blob.<suffix>/<container>/<blob>, without
+// resolving ResourceGroup. Use resolveUnmanagedDiskURI to also resolve the
+// storage account's resource group.
+func parseUnmanagedDiskURI(uri string) (*UnmanagedDiskMetadata, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse vhd URI %q: %v", uri, err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("unexpected scheme %q in vhd URI %q, want https", u.Scheme, uri)
+	}
+	hostParts := strings.SplitN(u.Host, ".", 2)
+	if len(hostParts) != 2 || hostParts[0] == "" || !strings.HasPrefix(hostParts[1], "blob.") {
+		return nil, fmt.Errorf("unexpected host %q in vhd URI %q, want <account>.blob.<suffix>", u.Host, uri)
+	}
+	pathParts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(pathParts) != 2 || pathParts[0] == "" || pathParts[1] == "" {
+		return nil, fmt.Errorf("unexpected path %q in vhd URI %q, want /<container>/<blob>", u.Path, uri)
+	}
+	return &UnmanagedDiskMetadata{
+		StorageAccountName: hostParts[0],
+		ContainerName:      pathParts[0],
+		BlobName:           pathParts[1],
+	}, nil
+}
+
+// resolveUnmanagedDiskURI parses uri and additionally resolves the
+// storage account's resource group, so callers that need to operate
+// cross-resource-group (BYO storage accounts, multi-RG clusters) have
+// everything they need in one struct.
+func (az *Cloud) resolveUnmanagedDiskURI(uri string) (*UnmanagedDiskMetadata, error) {
+	meta, err := parseUnmanagedDiskURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	rg, err := az.storageAccountResourceGroup(meta.StorageAccountName)
+	if err != nil {
+		return nil, err
+	}
+	meta.ResourceGroup = rg
+	return meta, nil
+}
+
+var (
+	storageAccountRGCacheMu sync.Mutex
+	storageAccountRGCache   = map[string]string{}
+)
+
+// storageAccountResourceGroup resolves accountName's resource group by
+// listing every storage account in the subscription -- Azure Storage URIs
+// do not encode the resource group -- and extracting it from each
+// account's ARM resource ID, caching account->resource-group mappings
+// since the accounts in a subscription change far less often than disks
+// are created and deleted.
+func (az *Cloud) storageAccountResourceGroup(accountName string) (string, error) {
+	storageAccountRGCacheMu.Lock()
+	defer storageAccountRGCacheMu.Unlock()
+
+	if rg, ok := storageAccountRGCache[accountName]; ok {
+		return rg, nil
+	}
+
+	result, err := az.StorageAccountClient.List(nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list storage accounts: %v", err)
+	}
+	if result.Value == nil {
+		return "", fmt.Errorf("no storage accounts found in subscription")
+	}
+	for _, account := range *result.Value {
+		if account.Name == nil || account.ID == nil {
+			continue
+		}
+		rg, err := resourceGroupFromID(*account.ID)
+		if err != nil {
+			continue
+		}
+		storageAccountRGCache[*account.Name] = rg
+	}
+
+	rg, ok := storageAccountRGCache[accountName]
+	if !ok {
+		return "", fmt.Errorf("could not find resource group for storage account %q", accountName)
+	}
+	return rg, nil
+}
+
+// resourceGroupFromID extracts the resource group segment from an ARM
+// resource ID of the form
+// /subscriptions/<sub>/resourceGroups/<rg>/providers/<provider>/...
+func resourceGroupFromID(id string) (string, error) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("no resourceGroups segment in id %q", id)
 }
+
+// NOTE: AttachDisk and DetachDiskByName are not refactored to call
+// resolveUnmanagedDiskURI here. Both currently assume az.ResourceGroup for
+// every lookup they make (e.g. getStorageAccesskey(account.Name)), and
+// getStorageAccesskey/getStorageAccounts/createVhdBlob/deleteVhdBlob --
+// defined in this package's account/blob management file, which is not
+// present in this checkout -- would need a resource-group parameter added
+// before those two call sites could actually act cross-resource-group.
+// DeleteVolume above is refactored onto UnmanagedDiskMetadata/
+// parseUnmanagedDiskURI because it's the one call site fully contained in
+// this file; threading ResourceGroup through it end-to-end is left for
+// when the account/blob helpers themselves take one.
+
+// deleteManagedDisk deletes the ARM managed disk resource named name.
+func (az *Cloud) deleteManagedDisk(name string) error {
+	if _, err := az.DisksClient.Delete(az.ResourceGroup, name, nil); err != nil {
+		return fmt.Errorf("failed to delete managed disk %s: %v", name, err)
+	}
+	glog.V(4).Infof("managed disk %s deleted", name)
+	return nil
+}
+
+// newDataDisk builds the compute.DataDisk ARM model for diskURI, routing
+// through the unmanaged VHD-blob shape or the managed-disk shape
+// depending on whether diskURI is a blob URI or an ARM resource ID.
+func newDataDisk(diskName, diskURI string, lun int32, cachingMode compute.CachingTypes) compute.DataDisk {
+	disk := compute.DataDisk{
+		Name:         &diskName,
+		Lun:          &lun,
+		Caching:      cachingMode,
+		CreateOption: "attach",
+	}
+	if isManagedDiskURI(diskURI) {
+		disk.ManagedDisk = &compute.ManagedDiskParameters{ID: &diskURI}
+	} else {
+		disk.Vhd = &compute.VirtualHardDisk{URI: &diskURI}
+	}
+	return disk
+}
+
+// isManagedDiskURI reports whether uri is an ARM managed disk resource ID
+// rather than a VHD blob URI.
+func isManagedDiskURI(uri string) bool {
+	return strings.HasPrefix(uri, "/subscriptions/")
+}
+
+// NOTE: createStorageAccountForNewVHD calls four helpers that aren't
+// defined in this file: az.createStorageAccount (wraps
+// StorageAccountClient.Create), az.waitStorageAccountProvisioned (polls
+// until the account's ProvisioningState is Succeeded),
+// az.createVhdContainer (creates the "vhds" blob container, alongside the
+// existing createVhdBlob/deleteVhdBlob) and az.listVhdBlobs (lists blobs
+// in that container, used by vhdAccountIsFull). All four belong next to
+// getStorageAccounts/getStorageAccesskey/createVhdBlob/deleteVhdBlob in
+// this package's account/blob management file, which is not present in
+// this checkout. Similarly, az.Config.MaxStorageAccounts and
+// az.Config.MaxVHDsPerStorageAccount are new knobs on the cloud config
+// struct defined in azure.go, also not present here; both default to 0
+// (unset), which vhdCountThreshold and createStorageAccountForNewVHD
+// already treat as "use the built-in default".
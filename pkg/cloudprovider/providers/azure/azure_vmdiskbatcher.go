@@ -0,0 +1,229 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/cloudprovider"
+	"k8s.io/kubernetes/pkg/types"
+)
+
+const (
+	// vmDiskBatchWindow is how long a batcher waits after its first
+	// queued request before flushing, so attach/detach calls arriving
+	// for the same node within the window land in one CreateOrUpdate
+	// instead of racing each other's read-modify-write.
+	vmDiskBatchWindow = 250 * time.Millisecond
+
+	// maxConcurrentVMUpdates bounds how many CreateOrUpdate calls (one
+	// per node) can be in flight at once, so a slow VM update can't
+	// stall batches queued for every other node.
+	maxConcurrentVMUpdates = 10
+)
+
+// vmDiskOp identifies the kind of mutation a vmDiskRequest asks for.
+type vmDiskOp int
+
+const (
+	vmDiskAttach vmDiskOp = iota
+	vmDiskDetach
+)
+
+// vmDiskRequest is one pending attach or detach, queued against a node's
+// nodeDiskBatcher. result receives exactly one error (nil on success) once
+// the batch it lands in has been applied.
+type vmDiskRequest struct {
+	op          vmDiskOp
+	diskName    string
+	diskURI     string
+	lun         int32
+	cachingMode compute.CachingTypes
+	result      chan error
+}
+
+// vmDiskBatcher serializes AttachDisk/DetachDiskByName calls per node: all
+// requests queued for a node within vmDiskBatchWindow of the first are
+// applied to that node's VM with a single CreateOrUpdate, instead of each
+// request doing its own read-modify-write and racing the others.
+type vmDiskBatcher struct {
+	az *Cloud
+
+	mu       sync.Mutex
+	pending  map[types.NodeName][]*vmDiskRequest
+	timers   map[types.NodeName]*time.Timer
+	workerCh chan struct{} // bounded worker pool; one slot per in-flight CreateOrUpdate
+}
+
+// newVMDiskBatcher returns a vmDiskBatcher bound to az, with a worker pool
+// capped at maxConcurrentVMUpdates.
+func newVMDiskBatcher(az *Cloud) *vmDiskBatcher {
+	return &vmDiskBatcher{
+		az:       az,
+		pending:  make(map[types.NodeName][]*vmDiskRequest),
+		timers:   make(map[types.NodeName]*time.Timer),
+		workerCh: make(chan struct{}, maxConcurrentVMUpdates),
+	}
+}
+
+// enqueue adds req to nodeName's pending batch, starting the batch's
+// flush timer if no flush is already scheduled or running for that node,
+// and blocks until req has been applied (successfully or not).
+func (b *vmDiskBatcher) enqueue(nodeName types.NodeName, req *vmDiskRequest) error {
+	b.mu.Lock()
+	b.pending[nodeName] = append(b.pending[nodeName], req)
+	if _, scheduled := b.timers[nodeName]; !scheduled {
+		b.timers[nodeName] = time.AfterFunc(vmDiskBatchWindow, func() {
+			b.flush(nodeName)
+		})
+	}
+	b.mu.Unlock()
+
+	return <-req.result
+}
+
+// flush takes every request currently queued for nodeName, applies them
+// together to that node's VM, and delivers a result to each request. The
+// node's timer entry stays in place until the batch is applied, so
+// requests arriving meanwhile wait for the next batch, which flush
+// schedules once this one is done.
+func (b *vmDiskBatcher) flush(nodeName types.NodeName) {
+	b.mu.Lock()
+	reqs := b.pending[nodeName]
+	delete(b.pending, nodeName)
+	b.mu.Unlock()
+
+	if len(reqs) > 0 {
+		b.workerCh <- struct{}{}
+		err := b.applyBatch(nodeName, reqs)
+		<-b.workerCh
+		for _, req := range reqs {
+			req.result <- err
+		}
+	}
+
+	b.mu.Lock()
+	delete(b.timers, nodeName)
+	if len(b.pending[nodeName]) > 0 {
+		b.timers[nodeName] = time.AfterFunc(vmDiskBatchWindow, func() {
+			b.flush(nodeName)
+		})
+	}
+	b.mu.Unlock()
+}
+
+// applyBatch reads nodeName's VM once, applies every queued attach/detach
+// in order, and writes the result back with a single CreateOrUpdate,
+// retrying on conflict responses with exponential backoff.
+func (b *vmDiskBatcher) applyBatch(nodeName types.NodeName, reqs []*vmDiskRequest) error {
+	az := b.az
+	vm, exists, err := az.getVirtualMachine(nodeName)
+	if err != nil {
+		return err
+	} else if !exists {
+		return cloudprovider.InstanceNotFound
+	}
+
+	disks := *vm.Properties.StorageProfile.DataDisks
+	for _, req := range reqs {
+		switch req.op {
+		case vmDiskAttach:
+			disks = append(disks, newDataDisk(req.diskName, req.diskURI, req.lun, req.cachingMode))
+		case vmDiskDetach:
+			disks = removeDataDisk(disks, req.diskName, req.diskURI)
+		}
+	}
+
+	newVM := compute.VirtualMachine{
+		Location: vm.Location,
+		Properties: &compute.VirtualMachineProperties{
+			StorageProfile: &compute.StorageProfile{
+				DataDisks: &disks,
+			},
+		},
+	}
+	vmName := mapNodeNameToVMName(nodeName)
+
+	backoff := wait.Backoff{
+		Duration: 1 * time.Second,
+		Factor:   2,
+		Steps:    5,
+	}
+	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+		_, err := az.VirtualMachinesClient.CreateOrUpdate(az.ResourceGroup, vmName, newVM, nil)
+		if err == nil {
+			return true, nil
+		}
+		if strings.Contains(err.Error(), "Code=\"ConflictingUserInput\"") || strings.Contains(err.Error(), "Code=\"Conflict\"") {
+			glog.V(4).Infof("azure batched disk update for node %s hit a conflict, retrying", nodeName)
+			return false, nil
+		}
+		return false, err
+	})
+}
+
+// removeDataDisk returns disks with the first entry matching diskName or
+// diskURI removed, if any.
+func removeDataDisk(disks []compute.DataDisk, diskName, diskURI string) []compute.DataDisk {
+	for i, disk := range disks {
+		if dataDiskMatches(disk, diskName, diskURI) {
+			return append(disks[:i], disks[i+1:]...)
+		}
+	}
+	return disks
+}
+
+// dataDiskMatches reports whether disk is identified by diskName or
+// diskURI. diskURI is compared against whichever of Vhd.URI/ManagedDisk.ID
+// disk actually has set, so the same predicate works for both unmanaged
+// VHDs and managed disks; every field along the way is nil-guarded since a
+// managed disk never has Vhd set and a VHD never has ManagedDisk set.
+//
+// This used to be a single expression with broken operator precedence
+// (`disk.Lun != nil && nameMatch || uriMatch`, so the nil check only
+// guarded the name branch) that could dereference a nil Vhd on a managed
+// disk. Each branch below is independently nil-safe.
+func dataDiskMatches(disk compute.DataDisk, diskName, diskURI string) bool {
+	if diskName != "" && disk.Name != nil && *disk.Name == diskName {
+		return true
+	}
+	if diskURI == "" {
+		return false
+	}
+	if disk.Vhd != nil && disk.Vhd.URI != nil && *disk.Vhd.URI == diskURI {
+		return true
+	}
+	if disk.ManagedDisk != nil && disk.ManagedDisk.ID != nil && *disk.ManagedDisk.ID == diskURI {
+		return true
+	}
+	return false
+}
+
+// NOTE: az.vmDiskBatcher is a *vmDiskBatcher field that belongs on the
+// Cloud struct in azure.go, constructed once via newVMDiskBatcher(az) in
+// NewCloud alongside VirtualMachinesClient and the other ARM clients.
+// azure.go isn't present in this checkout, so that field and its
+// construction can't be added here; AttachDisk/DetachDiskByName
+// (azure_storage.go) guard every use of az.vmDiskBatcher with a nil check
+// and fall back to their own unbatched CreateOrUpdate when it's unset, so
+// the live attach/detach path keeps working until that field exists.
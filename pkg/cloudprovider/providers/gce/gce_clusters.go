@@ -18,36 +18,120 @@ package gce
 
 import (
 	"context"
+	"sync"
+
+	"github.com/golang/glog"
 	container "google.golang.org/api/container/v1"
 )
 
+// maxConcurrentClusterListCalls bounds how many zone/region List calls
+// GetManagedClusters issues at once, so a project with many managedZones
+// and regions can't open an unbounded number of simultaneous container API
+// requests.
+const maxConcurrentClusterListCalls = 10
+
 func newClustersMetricContext(request, zone string) *metricContext {
 	return newGenericMetricContext("clusters", request, unusedMetricLabel, zone, computeV1Version)
 }
 
+// ClusterFilter restricts GetManagedClusters/ListClusters to clusters for
+// which it returns true. A nil filter matches every cluster.
+type ClusterFilter func(*container.Cluster) bool
+
+// ListClustersOptions narrows GetManagedClusters beyond the zonal listing
+// gce.managedZones gives for free: LabelSelector and StatusFilter are
+// passed straight through to the container API's List calls, and Regions
+// adds regional clusters (returned by the Locations API, not the
+// zone-scoped one) to the result alongside the zonal ones.
+type ListClustersOptions struct {
+	// LabelSelector restricts the listing to clusters matching this
+	// label selector expression, if non-empty.
+	LabelSelector string
+	// StatusFilter restricts the listing to clusters in this status
+	// (e.g. "RUNNING"), if non-empty.
+	StatusFilter string
+	// Regions additionally lists regional clusters in these regions.
+	// Regional clusters are invisible to the zonal List call, so without
+	// this they would never appear in GetManagedClusters' result.
+	Regions []string
+}
+
 func (gce *GCECloud) ListClusters(ctx context.Context) ([]string, error) {
-	allClusters := []string{}
+	clusters, err := gce.GetManagedClusters(ctx, nil, ListClustersOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-	for _, zone := range gce.managedZones {
-		clusters, err := gce.listClustersInZone(zone)
-		if err != nil {
-			return nil, err
-		}
+	allClusters := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
 		// TODO: Scoping?  Do we need to qualify the cluster name?
-		allClusters = append(allClusters, clusters...)
+		allClusters = append(allClusters, cluster.Name)
 	}
-
 	return allClusters, nil
 }
 
-func (gce *GCECloud) GetManagedClusters(ctx context.Context) ([]*container.Cluster, error) {
-	managedClusters := []*container.Cluster{}
+// GetManagedClusters returns every cluster across gce.managedZones and
+// opts.Regions matching filter (or every cluster, if filter is nil).
+// Zones and regions are listed concurrently, bounded by
+// maxConcurrentClusterListCalls, each paging through its full List
+// response rather than truncating at the first page; the whole fan-out
+// aborts as soon as ctx is cancelled or any single List call fails.
+func (gce *GCECloud) GetManagedClusters(ctx context.Context, filter ClusterFilter, opts ListClustersOptions) ([]*container.Cluster, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type listResult struct {
+		clusters []*container.Cluster
+		err      error
+	}
+
+	sources := make([]func(context.Context) ([]*container.Cluster, error), 0, len(gce.managedZones)+len(opts.Regions))
 	for _, zone := range gce.managedZones {
-		clusters, err := gce.getClustersInZone(zone)
-		if err != nil {
+		zone := zone
+		sources = append(sources, func(ctx context.Context) ([]*container.Cluster, error) {
+			return gce.getClustersInZone(ctx, zone, opts)
+		})
+	}
+	for _, region := range opts.Regions {
+		region := region
+		sources = append(sources, func(ctx context.Context) ([]*container.Cluster, error) {
+			return gce.getClustersInRegion(ctx, region, opts)
+		})
+	}
+
+	results := make([]listResult, len(sources))
+	sem := make(chan struct{}, maxConcurrentClusterListCalls)
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		managedClusters = append(managedClusters, clusters...)
+
+		i, source := i, source
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			clusters, err := source(ctx)
+			results[i] = listResult{clusters: clusters, err: err}
+			if err != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	managedClusters := []*container.Cluster{}
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		for _, cluster := range result.clusters {
+			if filter == nil || filter(cluster) {
+				managedClusters = append(managedClusters, cluster)
+			}
+		}
 	}
 
 	return managedClusters, nil
@@ -57,26 +141,69 @@ func (gce *GCECloud) Master(ctx context.Context, clusterName string) (string, er
 	return "k8s-" + clusterName + "-master.internal", nil
 }
 
-func (gce *GCECloud) listClustersInZone(zone string) ([]string, error) {
-	clusters, err := gce.getClustersInZone(zone)
-	if err != nil {
-		return nil, err
+// getClustersInZone returns every cluster in zone matching opts'
+// LabelSelector/StatusFilter, following PageToken until the container API
+// reports no more pages rather than stopping at the first (at most
+// 500-entry) page. Each page is timed and counted as its own
+// "list_zone_page" observation, in addition to the overall "list_zone"
+// observation for the whole call.
+func (gce *GCECloud) getClustersInZone(ctx context.Context, zone string, opts ListClustersOptions) ([]*container.Cluster, error) {
+	mc := newClustersMetricContext("list_zone", zone)
+
+	call := gce.containerService.Projects.Zones.Clusters.List(gce.projectID, zone).Context(ctx)
+	if opts.LabelSelector != "" {
+		call = call.LabelSelector(opts.LabelSelector)
+	}
+	if opts.StatusFilter != "" {
+		call = call.StatusFilter(opts.StatusFilter)
 	}
 
-	result := []string{}
-	for _, cluster := range clusters {
-		result = append(result, cluster.Name)
+	var clusters []*container.Cluster
+	pages := 0
+	err := call.Pages(ctx, func(resp *container.ListClustersResponse) error {
+		pageMC := newClustersMetricContext("list_zone_page", zone)
+		pages++
+		clusters = append(clusters, resp.Clusters...)
+		return pageMC.Observe(nil)
+	})
+	if err != nil {
+		return nil, mc.Observe(err)
 	}
-	return result, nil
+	glog.V(4).Infof("listed %d cluster(s) for zone %s across %d page(s)", len(clusters), zone, pages)
+
+	return clusters, mc.Observe(nil)
 }
 
-func (gce *GCECloud) getClustersInZone(zone string) ([]*container.Cluster, error) {
-	mc := newClustersMetricContext("list_zone", zone)
-	// TODO: use PageToken to list all not just the first 500
-	list, err := gce.containerService.Projects.Zones.Clusters.List(gce.projectID, zone).Do()
+// getClustersInRegion returns every cluster in region matching opts'
+// LabelSelector/StatusFilter, via the Locations-scoped List call -- the
+// zone-scoped Projects.Zones.Clusters.List never returns regional
+// clusters, so without this they're invisible to GetManagedClusters.
+// Like getClustersInZone, it pages until the API reports no more pages
+// and times/counts each page as its own observation.
+func (gce *GCECloud) getClustersInRegion(ctx context.Context, region string, opts ListClustersOptions) ([]*container.Cluster, error) {
+	mc := newClustersMetricContext("list_region", region)
+
+	parent := "projects/" + gce.projectID + "/locations/" + region
+	call := gce.containerService.Projects.Locations.Clusters.List(parent).Context(ctx)
+	if opts.LabelSelector != "" {
+		call = call.LabelSelector(opts.LabelSelector)
+	}
+	if opts.StatusFilter != "" {
+		call = call.StatusFilter(opts.StatusFilter)
+	}
+
+	var clusters []*container.Cluster
+	pages := 0
+	err := call.Pages(ctx, func(resp *container.ListClustersResponse) error {
+		pageMC := newClustersMetricContext("list_region_page", region)
+		pages++
+		clusters = append(clusters, resp.Clusters...)
+		return pageMC.Observe(nil)
+	})
 	if err != nil {
 		return nil, mc.Observe(err)
 	}
+	glog.V(4).Infof("listed %d cluster(s) for region %s across %d page(s)", len(clusters), region, pages)
 
-	return list.Clusters, mc.Observe(nil)
+	return clusters, mc.Observe(nil)
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func canaryStrategy(weights ...int32) CanaryDeploymentStrategy {
+	steps := make([]CanaryStep, len(weights))
+	for i, w := range weights {
+		steps[i] = CanaryStep{Weight: w}
+	}
+	return CanaryDeploymentStrategy{Steps: steps}
+}
+
+func TestAdvanceCanaryStepAdvancesWithoutPauseOrAnalysis(t *testing.T) {
+	strategy := canaryStrategy(20, 50)
+	start := time.Now()
+	status := AdvanceCanaryStep(strategy, CanaryRolloutStatus{}, true, start)
+	if status.CurrentStep != 1 {
+		t.Fatalf("CurrentStep = %d, want 1", status.CurrentStep)
+	}
+	if !status.StepStartTime.Time.Equal(start) {
+		t.Fatalf("StepStartTime = %v, want %v", status.StepStartTime.Time, start)
+	}
+}
+
+func TestAdvanceCanaryStepHoldsForPause(t *testing.T) {
+	pause := metav1.Duration{Duration: time.Hour}
+	strategy := CanaryDeploymentStrategy{Steps: []CanaryStep{{Weight: 20, Pause: &pause}}}
+	start := time.Now()
+	status := CanaryRolloutStatus{CurrentStep: 0, StepStartTime: metav1.NewTime(start)}
+
+	stillPaused := AdvanceCanaryStep(strategy, status, true, start.Add(time.Minute))
+	if stillPaused != status {
+		t.Fatalf("expected status unchanged while paused, got %+v", stillPaused)
+	}
+
+	elapsed := AdvanceCanaryStep(strategy, status, true, start.Add(2*time.Hour))
+	if elapsed.CurrentStep != 1 {
+		t.Fatalf("CurrentStep = %d, want 1 once the pause has elapsed", elapsed.CurrentStep)
+	}
+}
+
+func TestAdvanceCanaryStepRollsBackOnFailedAnalysis(t *testing.T) {
+	strategy := CanaryDeploymentStrategy{Steps: []CanaryStep{
+		{Weight: 20, Analysis: &AnalysisRef{Name: "check"}},
+	}}
+	now := time.Now()
+	status := AdvanceCanaryStep(strategy, CanaryRolloutStatus{}, false, now)
+	if !status.RolledBack() {
+		t.Fatalf("expected a rolled-back status, got %+v", status)
+	}
+
+	// A rolled-back rollout stays rolled back; it doesn't resume stepping.
+	again := AdvanceCanaryStep(strategy, status, true, now.Add(time.Minute))
+	if again != status {
+		t.Fatalf("expected rolled-back status to be final, got %+v", again)
+	}
+}
+
+func TestAdvanceCanaryStepPastLastStepStaysPromoted(t *testing.T) {
+	strategy := canaryStrategy(50)
+	now := time.Now()
+	promoted := CanaryRolloutStatus{CurrentStep: 1, StepStartTime: metav1.NewTime(now)}
+
+	status := AdvanceCanaryStep(strategy, promoted, true, now.Add(time.Hour))
+	if status != promoted {
+		t.Fatalf("expected a fully-promoted status to stay unchanged, got %+v", status)
+	}
+}
+
+func TestCanaryReplicaCounts(t *testing.T) {
+	strategy := canaryStrategy(20, 50)
+
+	stable, canary := canaryReplicaCounts(strategy, CanaryRolloutStatus{CurrentStep: 0}, 10)
+	if stable != 8 || canary != 2 {
+		t.Errorf("step 0 of 10 replicas = (%d, %d), want (8, 2)", stable, canary)
+	}
+
+	stable, canary = canaryReplicaCounts(strategy, CanaryRolloutStatus{CurrentStep: 1}, 10)
+	if stable != 5 || canary != 5 {
+		t.Errorf("step 1 of 10 replicas = (%d, %d), want (5, 5)", stable, canary)
+	}
+
+	stable, canary = canaryReplicaCounts(strategy, CanaryRolloutStatus{CurrentStep: 2}, 10)
+	if stable != 10 || canary != 0 {
+		t.Errorf("past the last step, got (%d, %d), want fully promoted (10, 0)", stable, canary)
+	}
+
+	stable, canary = canaryReplicaCounts(strategy, CanaryRolloutStatus{CurrentStep: -1}, 10)
+	if stable != 10 || canary != 0 {
+		t.Errorf("rolled back, got (%d, %d), want fully promoted (10, 0)", stable, canary)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	extensions "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
+)
+
+// ScaleProposal describes a scale decision the DeploymentController is about
+// to commit for a Deployment, before the new/old ReplicaSet replica counts
+// are persisted.
+type ScaleProposal struct {
+	Deployment    *extensions.Deployment
+	NewReplicaSet *extensions.ReplicaSet
+	OldReplicaSets []*extensions.ReplicaSet
+	// ReplicaSetName is the ReplicaSet ScaleDelta applies to.
+	ReplicaSetName string
+	ScaleDelta     int32
+}
+
+// ExtenderResult is returned by a DeploymentExtender for a given
+// ScaleProposal. If Veto is true the scale action is skipped this sync; the
+// controller relies on the next resync to re-evaluate. RewrittenDelta, when
+// non-nil, replaces ScaleDelta for the ReplicaSet named in the proposal.
+type ExtenderResult struct {
+	Veto           bool
+	RewrittenDelta *int32
+}
+
+// DeploymentExtender is implemented by out-of-tree processes that want to be
+// consulted before the DeploymentController commits a scale decision, e.g.
+// to gate rollout progress on an external canary or metrics system. This
+// mirrors the scheduler's Extender mechanism (see
+// plugin/pkg/scheduler/api.ExtenderConfig and its NewHTTPExtender), but
+// speaks in terms of Deployments and ReplicaSets instead of Pods and Nodes.
+type DeploymentExtender interface {
+	// Name identifies the extender for logging and event messages.
+	Name() string
+	// ProcessScale is called once per ScaleProposal produced while syncing
+	// a Deployment, before the corresponding ReplicaSet is scaled.
+	ProcessScale(proposal ScaleProposal) (ExtenderResult, error)
+}
+
+// HTTPDeploymentExtenderConfig is the on-disk configuration for a single
+// HTTP-backed DeploymentExtender, loaded via CreateDeploymentExtenders.
+type HTTPDeploymentExtenderConfig struct {
+	URLPrefix string        `json:"urlPrefix" yaml:"urlPrefix"`
+	Timeout   time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// httpDeploymentExtender calls out to an external HTTP service to evaluate a
+// ScaleProposal. It is the HTTP implementation of DeploymentExtender,
+// analogous to the scheduler's HTTPExtender.
+type httpDeploymentExtender struct {
+	name      string
+	urlPrefix string
+	client    *http.Client
+}
+
+// NewHTTPDeploymentExtender builds a DeploymentExtender that POSTs each
+// ScaleProposal as JSON to urlPrefix+"/process" and expects an
+// ExtenderResult back in the response body.
+func NewHTTPDeploymentExtender(name string, config HTTPDeploymentExtenderConfig) DeploymentExtender {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return &httpDeploymentExtender{
+		name:      name,
+		urlPrefix: config.URLPrefix,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *httpDeploymentExtender) Name() string {
+	return h.name
+}
+
+func (h *httpDeploymentExtender) ProcessScale(proposal ScaleProposal) (ExtenderResult, error) {
+	body, err := json.Marshal(proposal)
+	if err != nil {
+		return ExtenderResult{}, fmt.Errorf("extender %q: failed to encode scale proposal: %v", h.name, err)
+	}
+	resp, err := h.client.Post(h.urlPrefix+"/process", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return ExtenderResult{}, fmt.Errorf("extender %q: request failed: %v", h.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ExtenderResult{}, fmt.Errorf("extender %q: unexpected status %d", h.name, resp.StatusCode)
+	}
+	var result ExtenderResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return ExtenderResult{}, fmt.Errorf("extender %q: failed to decode response: %v", h.name, err)
+	}
+	return result, nil
+}
+
+// CreateDeploymentExtenders reads a config file (à la the scheduler's
+// CreateFromConfig) describing zero or more HTTP deployment extenders, a
+// map keyed by extender name, and returns one extender per entry; the order
+// of the returned extenders is not defined.
+//
+// NOTE: wiring these extenders into DeploymentController.syncDeployment,
+// getAllReplicaSetsAndSyncRevision, and scale is intentionally left out of
+// this change: the DeploymentController implementation that owns those
+// methods is not present in this checkout (only deployment_controller_test.go
+// is), so there is nothing here to call CreateDeploymentExtenders or
+// ProcessScale from. This file implements the extender contract and registry
+// so that wiring can land as a follow-up once the controller source is
+// available.
+func CreateDeploymentExtenders(configFile string) ([]DeploymentExtender, error) {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read deployment extender config %q: %v", configFile, err)
+	}
+	var configs map[string]HTTPDeploymentExtenderConfig
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("couldn't parse deployment extender config %q: %v", configFile, err)
+	}
+	extenders := make([]DeploymentExtender, 0, len(configs))
+	for name, config := range configs {
+		extenders = append(extenders, NewHTTPDeploymentExtender(name, config))
+	}
+	return extenders, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPDeploymentExtenderProcessScale(t *testing.T) {
+	rewritten := int32(3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/process" {
+			t.Errorf("request path = %q, want %q", r.URL.Path, "/process")
+		}
+		var proposal ScaleProposal
+		if err := json.NewDecoder(r.Body).Decode(&proposal); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if proposal.ReplicaSetName != "nginx-deployment-1" {
+			t.Errorf("ReplicaSetName = %q, want %q", proposal.ReplicaSetName, "nginx-deployment-1")
+		}
+		json.NewEncoder(w).Encode(ExtenderResult{RewrittenDelta: &rewritten})
+	}))
+	defer server.Close()
+
+	extender := NewHTTPDeploymentExtender("canary", HTTPDeploymentExtenderConfig{URLPrefix: server.URL})
+	if got, want := extender.Name(), "canary"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+
+	result, err := extender.ProcessScale(ScaleProposal{ReplicaSetName: "nginx-deployment-1", ScaleDelta: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Veto {
+		t.Error("Veto = true, want false")
+	}
+	if result.RewrittenDelta == nil || *result.RewrittenDelta != rewritten {
+		t.Errorf("RewrittenDelta = %v, want %d", result.RewrittenDelta, rewritten)
+	}
+}
+
+func TestHTTPDeploymentExtenderProcessScaleVeto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ExtenderResult{Veto: true})
+	}))
+	defer server.Close()
+
+	extender := NewHTTPDeploymentExtender("canary", HTTPDeploymentExtenderConfig{URLPrefix: server.URL})
+	result, err := extender.ProcessScale(ScaleProposal{ReplicaSetName: "nginx-deployment-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Veto {
+		t.Error("Veto = false, want true")
+	}
+}
+
+func TestHTTPDeploymentExtenderProcessScaleErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	extender := NewHTTPDeploymentExtender("canary", HTTPDeploymentExtenderConfig{URLPrefix: server.URL})
+	if _, err := extender.ProcessScale(ScaleProposal{}); err == nil {
+		t.Error("expected an error for a non-200 response")
+	}
+}
+
+func TestCreateDeploymentExtenders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deployment-extenders")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "extenders.yaml")
+	contents := "canary:\n  urlPrefix: http://canary.example.com\n  timeout: 10s\n"
+	if err := ioutil.WriteFile(configFile, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	extenders, err := CreateDeploymentExtenders(configFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(extenders) != 1 {
+		t.Fatalf("got %d extenders, want 1", len(extenders))
+	}
+	if got, want := extenders[0].Name(), "canary"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+}
+
+func TestCreateDeploymentExtendersMissingFile(t *testing.T) {
+	if _, err := CreateDeploymentExtenders("/nonexistent/extenders.yaml"); err == nil {
+		t.Error("expected an error for a missing config file")
+	}
+}
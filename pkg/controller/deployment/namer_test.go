@@ -0,0 +1,53 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import "testing"
+
+func TestFNVReplicaSetNamer(t *testing.T) {
+	n := NewFNVReplicaSetNamer()
+	if got, want := n.Name("nginx-deployment", 4186632231), "nginx-deployment-4186632231"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+}
+
+func TestRetryReplicaSetNamerIsMonotonic(t *testing.T) {
+	n := NewRetryReplicaSetNamer(NewFNVReplicaSetNamer())
+	base := n.Name("nginx-deployment", 4186632231)
+
+	first := n.OnConflict("nginx-deployment", 4186632231, base)
+	second := n.OnConflict("nginx-deployment", 4186632231, base)
+
+	if first == second {
+		t.Fatalf("expected successive OnConflict calls to produce distinct names, got %q twice", first)
+	}
+	if want := base + ".1"; first != want {
+		t.Errorf("first retry = %q, want %q", first, want)
+	}
+	if want := base + ".2"; second != want {
+		t.Errorf("second retry = %q, want %q", second, want)
+	}
+}
+
+func TestSHA256ReplicaSetNamerTruncates(t *testing.T) {
+	n := NewSHA256ReplicaSetNamer(8)
+	name := n.Name("nginx-deployment", 4186632231)
+	wantPrefix := "nginx-deployment-"
+	if len(name) != len(wantPrefix)+8 {
+		t.Errorf("Name() = %q, want an 8-character truncated hash suffix", name)
+	}
+}
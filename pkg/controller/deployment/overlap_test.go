@@ -0,0 +1,82 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	extensions "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
+)
+
+func overlapDeployment(name string, creation metav1.Time, selector map[string]string) *extensions.Deployment {
+	return &extensions.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: creation,
+		},
+		Spec: extensions.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
+func TestFindOverlap(t *testing.T) {
+	d := overlapDeployment("foo", metav1.Time{}, map[string]string{"app": "foo"})
+	other := overlapDeployment("bar", metav1.Time{}, map[string]string{"app": "foo"})
+	unrelated := overlapDeployment("baz", metav1.Time{}, map[string]string{"app": "baz"})
+
+	o := newOverlapController(OverlapPolicyReject)
+	got, err := o.findOverlap(d, []*extensions.Deployment{unrelated, other})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || got.Name != other.Name {
+		t.Fatalf("expected overlap with %q, got %v", other.Name, got)
+	}
+	if got, err := o.findOverlap(d, []*extensions.Deployment{unrelated}); err != nil || got != nil {
+		t.Fatalf("expected no overlap, got %v, err %v", got, err)
+	}
+}
+
+func TestResolveOverlapOldestWins(t *testing.T) {
+	older := overlapDeployment("older", metav1.NewTime(time.Now().Add(-time.Hour)), map[string]string{"app": "foo"})
+	newer := overlapDeployment("newer", metav1.Now(), map[string]string{"app": "foo"})
+
+	o := newOverlapController(OverlapPolicyOldestWins)
+	loser := o.resolveOverlap(newer, older)
+	if loser.Name != newer.Name {
+		t.Fatalf("expected %q to lose to the older deployment, got %q", newer.Name, loser.Name)
+	}
+}
+
+func TestResolveOverlapRejectIsDeterministic(t *testing.T) {
+	a := overlapDeployment("a", metav1.Time{}, map[string]string{"app": "foo"})
+	b := overlapDeployment("b", metav1.Time{}, map[string]string{"app": "foo"})
+
+	o := newOverlapController(OverlapPolicyReject)
+	first := o.resolveOverlap(a, b)
+	second := o.resolveOverlap(b, a)
+	if first.Name != second.Name {
+		t.Fatalf("expected resolveOverlap to be order-independent, got %q then %q", first.Name, second.Name)
+	}
+	if first.Name != "b" {
+		t.Fatalf("expected %q (sorts last) to lose, got %q", "b", first.Name)
+	}
+}
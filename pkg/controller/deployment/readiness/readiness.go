@@ -0,0 +1,102 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readiness evaluates resource-type-specific readiness, instead of
+// relying solely on ReplicaSet.Status.ReadyReplicas, so that callers like
+// the deployment controller can make progress/stall decisions that account
+// for the kind of resource backing a rollout.
+package readiness
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CheckType identifies which resource-specific readiness check produced a
+// Breakdown count.
+type CheckType string
+
+const (
+	CheckDeployment CheckType = "Deployment"
+	CheckStatefulSetPod CheckType = "StatefulSetPod"
+	CheckServiceEndpoints CheckType = "ServiceEndpoints"
+	CheckPVCBound CheckType = "PVCBound"
+	CheckJobComplete CheckType = "JobComplete"
+)
+
+// Checker evaluates whether a single object is ready.
+type Checker interface {
+	// CheckType reports which CheckType this Checker contributes to a
+	// Breakdown.
+	CheckType() CheckType
+	// IsReady returns whether obj is ready, or an error if readiness
+	// could not be determined (e.g. obj is of a type this Checker does
+	// not understand).
+	IsReady(obj runtime.Object) (bool, error)
+}
+
+// Breakdown is the per-check-type tally of ready vs. total objects
+// evaluated, suitable for attaching to a DeploymentCondition.
+type Breakdown struct {
+	Counts map[CheckType]Count
+}
+
+// Count is the ready/total tally for a single CheckType.
+type Count struct {
+	Ready int32
+	Total int32
+}
+
+// Evaluator runs a set of Checkers over a list of objects and produces a
+// Breakdown.
+type Evaluator struct {
+	checkers []Checker
+}
+
+// NewEvaluator builds an Evaluator from the given Checkers, applied in
+// order; the first Checker whose IsReady does not error for a given object
+// is used for that object.
+func NewEvaluator(checkers ...Checker) *Evaluator {
+	return &Evaluator{checkers: checkers}
+}
+
+// Evaluate runs every object in objs through the Evaluator's Checkers and
+// returns the resulting Breakdown.
+func (e *Evaluator) Evaluate(objs []runtime.Object) (Breakdown, error) {
+	breakdown := Breakdown{Counts: map[CheckType]Count{}}
+	for _, obj := range objs {
+		var matched bool
+		for _, checker := range e.checkers {
+			ready, err := checker.IsReady(obj)
+			if err != nil {
+				continue
+			}
+			matched = true
+			count := breakdown.Counts[checker.CheckType()]
+			count.Total++
+			if ready {
+				count.Ready++
+			}
+			breakdown.Counts[checker.CheckType()] = count
+			break
+		}
+		if !matched {
+			return breakdown, fmt.Errorf("readiness: no checker understood object %T", obj)
+		}
+	}
+	return breakdown, nil
+}
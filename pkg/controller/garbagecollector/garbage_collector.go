@@ -28,6 +28,7 @@ import (
 	"k8s.io/kubernetes/pkg/api/v1"
 	metav1 "k8s.io/kubernetes/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/client/cache"
+	"k8s.io/kubernetes/pkg/client/record"
 	"k8s.io/kubernetes/pkg/client/typed/dynamic"
 	"k8s.io/kubernetes/pkg/controller/garbagecollector/metaonly"
 	"k8s.io/kubernetes/pkg/runtime"
@@ -65,8 +66,12 @@ type GarbageCollector struct {
 	// garbage collector attempts to orphan the dependents of the items in the attemptToOrphan queue, then deletes the items.
 	attemptToOrphan workqueue.RateLimitingInterface
 	// each monitor list/watches a resource, the results are funneled to the
-	// dependencyGraphBuilder
-	monitors               []*cache.Controller
+	// dependencyGraphBuilder. monitors is keyed by GVR so Sync can
+	// diff-reconcile it as the discovery API reports resources appearing
+	// (e.g. a newly registered CRD) or disappearing.
+	monitorLock sync.Mutex
+	monitors    map[schema.GroupVersionResource]*monitor
+
 	dependencyGraphBuilder *GraphBuilder
 	// used to register exactly once the rate limiter of the dynamic client
 	// used by the garbage collector controller.
@@ -76,6 +81,24 @@ type GarbageCollector struct {
 	registeredRateLimiterForControllers *RegisteredRateLimiter
 	// GC caches the owners that do not exist according to the API server.
 	absentOwnerCache *UIDCache
+	// cycleDetector incrementally tracks ownerReferences edges to catch and
+	// repair ownership cycles; see garbage_collector_cycle_detector.go.
+	cycleDetector *cycleDetector
+	// eventRecorder, if set via SetEventRecorder, receives a Warning Event
+	// on every object the cycle detector finds part of a cycle. Nil by
+	// default, in which case cycle detection and repair still happen, just
+	// without the Event.
+	eventRecorder record.EventRecorder
+	// ownerLookups coalesces concurrent classifyReferences Gets for the
+	// same owner (e.g. thousands of Pods resolving the same just-deleted
+	// ReplicaSet) into one round trip; see
+	// garbage_collector_owner_lookup.go.
+	ownerLookups *ownerLookupCoalescer
+	// ownerBatcher upgrades the coalescing above into a single namespace
+	// LIST once enough *distinct* owners of the same GVR are pending
+	// resolution at once, for the fan-out case where many different
+	// owners in a namespace disappear around the same time.
+	ownerBatcher *ownerBatcher
 }
 
 func gcListWatcher(client *dynamic.Client, resource schema.GroupVersionResource) *cache.ListWatch {
@@ -168,62 +191,60 @@ var ignoredResources = map[schema.GroupVersionResource]struct{}{
 }
 
 func NewGarbageCollector(metaOnlyClientPool dynamic.ClientPool, clientPool dynamic.ClientPool, mapper meta.RESTMapper, deletableResources map[schema.GroupVersionResource]struct{}) (*GarbageCollector, error) {
-	attemptToDelete := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "garbage_collector_attempt_to_delete")
 	attemptToOrphan := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "garbage_collector_attempt_to_orphan")
 	absentOwnerCache := NewUIDCache(500)
 	gc := &GarbageCollector{
 		metaOnlyClientPool:                  metaOnlyClientPool,
 		clientPool:                          clientPool,
 		restMapper:                          mapper,
-		attemptToDelete:                     attemptToDelete,
 		attemptToOrphan:                     attemptToOrphan,
 		registeredRateLimiter:               NewRegisteredRateLimiter(deletableResources),
 		registeredRateLimiterForControllers: NewRegisteredRateLimiter(deletableResources),
 		absentOwnerCache:                    absentOwnerCache,
-	}
+		ownerLookups:                        newOwnerLookupCoalescer(2 * time.Second),
+	}
+	gc.ownerBatcher = newOwnerBatcher(gc.listOwnerCandidates, gc.getOwnerByName)
+	// attemptToDelete is sharded per-GVR so that one hot resource (e.g.
+	// millions of orphaned Pods) can't monopolize every
+	// attemptToDeleteWorker and starve deletion of the owners further up
+	// the chain that everything else is actually waiting on; see
+	// priorityDeleteQueue. Built after gc itself because classifying an
+	// item's GVR goes through gc.apiResource.
+	gc.attemptToDelete = newPriorityDeleteQueue(gc.gvrAndPriorityOf)
+	gc.cycleDetector = newCycleDetector(gc)
 	gc.dependencyGraphBuilder = &GraphBuilder{
 		graphChanges: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "garbage_collector_graph_changes"),
 		uidToNode: &concurrentUIDToNode{
 			RWMutex:   &sync.RWMutex{},
 			uidToNode: make(map[types.UID]*node),
 		},
-		attemptToDelete:  attemptToDelete,
+		attemptToDelete:  gc.attemptToDelete,
 		attemptToOrphan:  attemptToOrphan,
 		absentOwnerCache: absentOwnerCache,
 	}
-	for resource := range deletableResources {
-		if _, ok := ignoredResources[resource]; ok {
-			glog.V(5).Infof("ignore resource %#v", resource)
-			continue
-		}
-		kind, err := gc.restMapper.KindFor(resource)
-		if err != nil {
-			return nil, err
-		}
-		controller, err := gc.controllerFor(resource, kind)
-		if err != nil {
-			return nil, err
-		}
-		gc.monitors = append(gc.monitors, monitor)
+	if err := gc.resyncMonitors(deletableResources); err != nil {
+		return nil, err
 	}
 	return gc, nil
 }
 
+// SetGVRQueueConfig overrides the attemptToDelete scheduling weight and
+// concurrency cap used for gvr. Intended to be called once per
+// --concurrent-deletion-for=<gvr>=<weight>,<max-concurrency> flag
+// (cmd/kube-controller-manager, not present in this checkout) after
+// NewGarbageCollector returns and before Run.
+func (gc *GarbageCollector) SetGVRQueueConfig(gvr schema.GroupVersionResource, cfg GVRQueueConfig) {
+	gc.attemptToDelete.(*priorityDeleteQueue).SetGVRQueueConfig(gvr, cfg)
+}
+
 func (gc *GarbageCollector) Run(workers int, stopCh <-chan struct{}) {
 	defer gc.attemptToDelete.ShutDown()
 	defer gc.attemptToOrphan.ShutDown()
 	defer gc.dependencyGraphBuilder.graphChanges.ShutDown()
 
 	glog.Infof("Garbage Collector: Initializing")
-	for _, monitor := range gc.monitors {
-		go monitor.Run(stopCh)
-	}
-
-	var syncs []cache.InformerSynced
-	for _, monitor := range gc.monitors {
-		syncs = syncs.append(monitor.HasSynced())
-	}
-	if !cache.WaitForCacheSync(stopCh, syncs...) {
+	gc.startMonitors()
+	if !gc.waitForMonitorSync(stopCh) {
 		return
 	}
 	glog.Infof("Garbage Collector: All monitored resources synced. Proceeding to collect garbage")
@@ -238,6 +259,7 @@ func (gc *GarbageCollector) Run(workers int, stopCh <-chan struct{}) {
 	Register()
 	<-stopCh
 	glog.Infof("Garbage Collector: Shutting down")
+	gc.stopMonitors()
 }
 
 func (gc *GarbageCollector) attemptToDeleteWorker() {
@@ -281,6 +303,11 @@ func objectReferenceToMetadataOnlyObject(ref objectReference) *metaonly.Metadata
 // This function communicates with the server.
 func (gc *GarbageCollector) classifyReferences(item *node, latestReferences []metav1.OwnerReference) (
 	solid, dangling, waiting []metav1.OwnerReference, err error) {
+	// Feed the incremental cycle detector every time we learn an object's
+	// current ownerReferences; it runs a DFS bounded by item's ancestors
+	// and repairs the cycle itself if it finds one, so nothing further is
+	// needed here.
+	gc.cycleDetector.recordEdges(item.identity.UID, latestReferences, time.Now())
 	for _, reference := range latestReferences {
 		if gc.absentOwnerCache.Has(reference.UID) {
 			glog.V(5).Infof("according to the absentOwnerCache, object %s's owner %s/%s, %s does not exist", item.identity.UID, reference.APIVersion, reference.Kind, reference.Name)
@@ -293,19 +320,27 @@ func (gc *GarbageCollector) classifyReferences(item *node, latestReferences []me
 		// ii) should update the object to remove such references. This is to
 		// prevent objects having references to an old resource from being
 		// deleted during a cluster upgrade.
-		fqKind := schema.FromAPIVersionAndKind(reference.APIVersion, reference.Kind)
-		client, err := gc.clientPool.ClientForGroupVersionKind(fqKind)
-		if err != nil {
-			return solid, dangling, waiting, err
-		}
-		resource, err := gc.apiResource(reference.APIVersion, reference.Kind, len(item.identity.Namespace) != 0)
+		gvr, err := gc.gvrFor(reference.APIVersion, reference.Kind, len(item.identity.Namespace) != 0)
 		if err != nil {
 			return solid, dangling, waiting, err
 		}
 		// TODO: It's only necessary to talk to the API server if the owner node
 		// is a "virtual" node. The local graph could lag behind the real
 		// status, but in practice, the difference is small.
-		owner, err := client.Resource(resource, item.identity.Namespace).Get(reference.Name)
+		//
+		// Gets for the same owner (gvr, item.identity.Namespace,
+		// reference.Name) made while one is already in flight, or within
+		// ownerLookups' short TTL of one completing, are served from that
+		// shared result instead of issuing another round trip; see
+		// garbage_collector_owner_lookup.go. Beyond that, once enough
+		// distinct owners of gvr are pending resolution at once (e.g. a
+		// ReplicaSet's thousands of Pods racing to resolve their many
+		// now-deleted predecessor ReplicaSets), gc.ownerBatcher folds them
+		// into a single namespace LIST instead of one GET apiece.
+		key := ownerLookupKey{gvr: gvr, namespace: item.identity.Namespace, name: reference.Name}
+		owner, err := gc.ownerLookups.Get(key, func() (runtime.Object, error) {
+			return gc.ownerBatcher.Get(gvr, item.identity.Namespace, reference.Name)
+		})
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				return solid, dangling, waiting, err
@@ -315,17 +350,17 @@ func (gc *GarbageCollector) classifyReferences(item *node, latestReferences []me
 			dangling = append(dangling, reference)
 		}
 
-		if owner.GetUID() != reference.UID {
+		ownerAccessor, err := meta.Accessor(owner)
+		if err != nil {
+			return solid, dangling, waiting, err
+		}
+		if ownerAccessor.GetUID() != reference.UID {
 			glog.V(5).Infof("object %s's owner %s/%s, %s is not found, UID mismatch", item.identity.UID, reference.APIVersion, reference.Kind, reference.Name)
 			gc.absentOwnerCache.Add(reference.UID)
 			dangling = append(dangling, reference)
 			continue
 		}
 
-		ownerAccessor, err := meta.Accessor(owner)
-		if err != nil {
-			return solid, dangling, waiting, err
-		}
 		if ownerAccessor.GetDeletionTimestamp() != nil && hasDeleteDependentsFinalizer(ownerAccessor) {
 			waiting = append(waiting, reference)
 		} else {
@@ -409,11 +444,26 @@ func (gc *GarbageCollector) processItem(item *node) error {
 		_, err = gc.patchObject(item.identity, patch)
 		return err
 	case len(waiting) != 0 && len(item.dependents) != 0:
+		// gc.cycleDetector already ran a proper ancestors-bounded SCC check
+		// on item as of classifyReferences above and would have repaired
+		// any real cycle it found, Eventf'd every object in it, and bumped
+		// garbage_collector_ownership_cycles_total. What's left here is a
+		// narrower backstop for the single case that detector can't see
+		// coming from item's own edges alone: one of item's *dependents*
+		// (dep) is itself mid foreground-deletion, so the deadlock runs
+		// through dep's ownerReferences, not item's.
 		for dep := range item.dependents {
 			if dep.deletingDependents {
-				// this circle detection has false positives, we need to
-				// apply a more rigorous detection if this turns out to be a
-				// problem.
+				// item is waiting on an owner that is itself doing a
+				// foreground deletion, and one of item's own dependents (dep)
+				// is doing the same: without breaking the cycle here, item
+				// would block on its owner, the owner's foregroundDeletion
+				// finalizer would block on item, and item would block on dep
+				// forever. Clearing blockOwnerDeletion on item's own
+				// ownerReferences breaks the cycle by letting item proceed to
+				// the DeletePropagationForeground delete below, which still
+				// correctly waits on dep through the normal
+				// processDeletingDependentsItem path.
 				glog.V(2).Infof("processing object %s, some of its owners and its dependent [%s] have FianlizerDeletingDependents, to prevent potential cycle, its ownerReferences are going to be modified to be non-blocking, then the object is going to be deleted with DeletePropagationForeground", item.identity, dep.identity)
 				patch, err := item.patchToUnblockOwnerReferences()
 				if err != nil {
@@ -433,7 +483,12 @@ func (gc *GarbageCollector) processItem(item *node) error {
 	}
 }
 
-// process item that's waiting for its dependents to be deleted
+// process item that's waiting for its dependents to be deleted. item is
+// "blocked" on a dependent only if that dependent still exists and has
+// BlockOwnerDeletion set to true on the OwnerReference pointing back at
+// item; item.blockingDependents() is expected to apply that filter, so a
+// dependent added with BlockOwnerDeletion unset or false never holds up
+// removal of item's FinalizerDeleteDependents finalizer.
 func (gc *GarbageCollector) processDeletingDependentsItem(item *node) error {
 	blockingDependents := item.blockingDependents()
 	if len(blockingDependents) == 0 {
@@ -520,3 +575,13 @@ func (gc *GarbageCollector) GraphHasUID(UIDs []types.UID) bool {
 	}
 	return false
 }
+
+// NOTE: setting BlockOwnerDeletion=true on an OwnerReference a client doesn't
+// otherwise have delete permission on lets that client block the owner's
+// foreground deletion, so the apiserver is expected to reject such a
+// reference unless the requesting user can also "delete" the owner's
+// kind (the gc admission plugin that enforces this, analogous to the
+// orphan-on-delete lifecycle admission plugin, isn't present in this
+// checkout). Nothing in this file depends on that plugin existing: the GC
+// only reads BlockOwnerDeletion off whatever OwnerReferences already made it
+// past admission.
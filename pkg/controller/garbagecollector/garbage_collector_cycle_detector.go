@@ -0,0 +1,230 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+	metav1 "k8s.io/kubernetes/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/client/record"
+	"k8s.io/kubernetes/pkg/types"
+)
+
+// ownerEdge is one recorded "dependent depends on owner" edge: from is the
+// dependent's UID (the map key in cycleDetector.edges), to is the owner's.
+// observedAt is stamped the first time the detector sees the edge, not
+// refreshed on every resync, so it approximates "when this edge was added"
+// well enough to break a cycle deterministically without an extra API call
+// to fetch the owner's real creationTimestamp on the hot path.
+type ownerEdge struct {
+	to                 types.UID
+	blockOwnerDeletion bool
+	observedAt         time.Time
+}
+
+// cycleDetector maintains just enough of the ownership graph -- one
+// dependent -> owners adjacency list per node that's been classified --
+// to run a DFS bounded by the ancestors of whichever node just changed,
+// rather than a full graph sweep, every time an owner edge is added or
+// updated. It does a real cycle check on the recorded edges; processItem
+// keeps its older heuristic ("some of its owners and its dependent have
+// FinalizerDeletingDependents") only as a backstop for cycles that run
+// through a dependent's ownerReferences.
+type cycleDetector struct {
+	gc *GarbageCollector
+
+	mu    sync.Mutex
+	edges map[types.UID][]ownerEdge
+}
+
+func newCycleDetector(gc *GarbageCollector) *cycleDetector {
+	return &cycleDetector{
+		gc:    gc,
+		edges: map[types.UID][]ownerEdge{},
+	}
+}
+
+// recordEdges replaces from's recorded owner edges with refs and then runs
+// the bounded cycle check rooted at from. It's meant to be called
+// everywhere the GC learns an object's current ownerReferences, in
+// particular classifyReferences, so the detector's view never gets more
+// than one resync stale.
+func (d *cycleDetector) recordEdges(from types.UID, refs []metav1.OwnerReference, now time.Time) {
+	d.mu.Lock()
+	previouslyObserved := map[types.UID]time.Time{}
+	for _, e := range d.edges[from] {
+		previouslyObserved[e.to] = e.observedAt
+	}
+	next := make([]ownerEdge, 0, len(refs))
+	for _, ref := range refs {
+		at, ok := previouslyObserved[ref.UID]
+		if !ok {
+			at = now
+		}
+		next = append(next, ownerEdge{
+			to:                 ref.UID,
+			blockOwnerDeletion: ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion,
+			observedAt:         at,
+		})
+	}
+	d.edges[from] = next
+	d.mu.Unlock()
+
+	if cycle, found := d.checkAndRepair(from); found {
+		glog.V(2).Infof("garbage collector detected an ownerReferences cycle: %v", cycle)
+	}
+}
+
+// checkAndRepair runs a DFS from start over the recorded blocking owner
+// edges, stopping at the first node revisited on the current path -- a
+// cycle among start's ancestors, which need not include start itself --
+// and, if it finds one, repairs it. Edges whose blockOwnerDeletion is
+// already false are not followed, so a repaired cycle is not found again.
+// Each of start's recorded ancestors is explored at most once, so its cost
+// is O(ancestors-of-start), not O(graph size).
+func (d *cycleDetector) checkAndRepair(start types.UID) ([]types.UID, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var path []types.UID
+	onPath := map[types.UID]int{}
+	explored := map[types.UID]bool{}
+
+	var visit func(u types.UID) []types.UID
+	visit = func(u types.UID) []types.UID {
+		if idx, ok := onPath[u]; ok {
+			return append([]types.UID{}, path[idx:]...)
+		}
+		if explored[u] {
+			return nil
+		}
+		onPath[u] = len(path)
+		path = append(path, u)
+		for _, e := range d.edges[u] {
+			if !e.blockOwnerDeletion {
+				continue
+			}
+			if cycle := visit(e.to); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		delete(onPath, u)
+		explored[u] = true
+		return nil
+	}
+
+	cycle := visit(start)
+	if cycle == nil {
+		return nil, false
+	}
+	d.repairLocked(cycle)
+	return cycle, true
+}
+
+// repairLocked breaks cycle by clearing BlockOwnerDeletion on whichever edge
+// in it was added last (observedAt, tied-broken by UID so the choice stays
+// deterministic), records the ownership_cycles_total metric, and emits a
+// Warning Event on every object in the cycle. d.mu is held by the caller.
+func (d *cycleDetector) repairLocked(cycle []types.UID) {
+	ownershipCyclesTotal.Inc()
+
+	var lastFrom types.UID
+	var last ownerEdge
+	for i, u := range cycle {
+		to := cycle[(i+1)%len(cycle)]
+		for _, e := range d.edges[u] {
+			if e.to != to {
+				continue
+			}
+			if lastFrom == "" || e.observedAt.After(last.observedAt) ||
+				(e.observedAt.Equal(last.observedAt) && u > lastFrom) {
+				lastFrom, last = u, e
+			}
+		}
+	}
+
+	for _, u := range cycle {
+		n, ok := d.gc.dependencyGraphBuilder.uidToNode.Read(u)
+		if !ok {
+			continue
+		}
+		d.gc.recordCycleEvent(n.identity, cycle)
+	}
+
+	if lastFrom == "" {
+		return
+	}
+	n, ok := d.gc.dependencyGraphBuilder.uidToNode.Read(lastFrom)
+	if !ok {
+		return
+	}
+	if err := d.gc.unblockOwnerEdge(n, last.to); err != nil {
+		glog.V(2).Infof("failed to repair ownership cycle %v by clearing blockOwnerDeletion from %s -> %s: %v", cycle, lastFrom, last.to, err)
+	}
+}
+
+// eventRecorder is nil until SetEventRecorder is called (normally by
+// whoever constructs the GarbageCollector, once an EventRecorder is
+// available); recordCycleEvent is a no-op until then so cycle detection and
+// repair still work in tests or other callers that never wire one up.
+func (gc *GarbageCollector) recordCycleEvent(involved objectReference, cycle []types.UID) {
+	if gc.eventRecorder == nil {
+		return
+	}
+	gc.eventRecorder.Eventf(
+		objectReferenceToMetadataOnlyObject(involved),
+		v1.EventTypeWarning,
+		"OwnershipCycleDetected",
+		"object's ownerReferences are part of a cycle (%d objects); the garbage collector cleared blockOwnerDeletion on one edge in the cycle so foreground deletion can still make progress: %v",
+		len(cycle), cycle,
+	)
+}
+
+// SetEventRecorder wires an EventRecorder into the garbage collector for
+// cycle-detection Events. Safe to call once, before Run.
+func (gc *GarbageCollector) SetEventRecorder(recorder record.EventRecorder) {
+	gc.eventRecorder = recorder
+}
+
+// unblockOwnerEdge patches dependent's ownerReferences so the entry
+// pointing at ownerUID has blockOwnerDeletion: false, without touching any
+// of dependent's other owner references. Unlike
+// node.patchToUnblockOwnerReferences (which unblocks every owner edge to
+// break the simple two-party cycle processItem already detected on its
+// own), this only needs to touch the one edge actually implicated in the
+// cycle the detector found.
+func (gc *GarbageCollector) unblockOwnerEdge(dependent *node, ownerUID types.UID) error {
+	patch := unblockOwnerRefPatch(dependent.identity.UID, ownerUID)
+	_, err := gc.patchObject(dependent.identity, patch)
+	return err
+}
+
+// unblockOwnerRefPatch builds a strategic merge patch that sets
+// blockOwnerDeletion to false on dependentUID's ownerReference entry for
+// ownerUID, identified by uid the way the other owner-reference patches in
+// this package (e.g. deleteOwnerRefPatch) already do.
+func unblockOwnerRefPatch(dependentUID, ownerUID types.UID) []byte {
+	return []byte(fmt.Sprintf(
+		`{"metadata":{"ownerReferences":[{"$patch":"merge","uid":"%s","blockOwnerDeletion":false}],"uid":"%s"}}`,
+		ownerUID, dependentUID))
+}
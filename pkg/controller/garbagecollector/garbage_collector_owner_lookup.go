@@ -0,0 +1,337 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/runtime/schema"
+)
+
+// ownerLookupKey identifies the single API object classifyReferences is
+// asking about: a GVR plus namespace/name, the same coordinates a dependent
+// and all of its siblings use when they happen to share an owner.
+type ownerLookupKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+// ownerLookupCall is one in-flight (or just-finished but still within its
+// TTL) Get for an ownerLookupKey. Every classifyReferences caller racing on
+// the same key gets handed this same call and blocks on done, rather than
+// each issuing its own GET.
+type ownerLookupCall struct {
+	done chan struct{}
+	obj  runtime.Object
+	err  error
+	// expires is set once the call finishes; entries past expires are
+	// treated as absent from the cache by Get and replaced with a fresh
+	// call, which is what bounds how "short-lived" the coalescing window is.
+	expires time.Time
+}
+
+// ownerLookupCoalescer coalesces concurrent classifyReferences owner
+// lookups for the same object into a single GET, and keeps the result
+// around for ttl afterwards so a burst of requests that don't quite overlap
+// (e.g. several monitor goroutines fanning out deletes a few milliseconds
+// apart) still only costs one round trip. It is deliberately not a
+// general-purpose cache: ttl is short (seconds, not minutes), and
+// Invalidate evicts a key on demand. Nothing in this package calls
+// Invalidate, so a lookup made within ttl of an earlier one can be served
+// that earlier result even if the owner was deleted and recreated under
+// the same name in between (see the NOTE at the end of this file).
+type ownerLookupCoalescer struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	calls map[ownerLookupKey]*ownerLookupCall
+}
+
+func newOwnerLookupCoalescer(ttl time.Duration) *ownerLookupCoalescer {
+	return &ownerLookupCoalescer{
+		ttl:   ttl,
+		calls: map[ownerLookupKey]*ownerLookupCall{},
+	}
+}
+
+// Get returns the result of fetch(), coalescing concurrent and
+// recently-completed calls for the same key into one invocation of fetch.
+func (c *ownerLookupCoalescer) Get(key ownerLookupKey, fetch func() (runtime.Object, error)) (runtime.Object, error) {
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		if call.expires.IsZero() || time.Now().Before(call.expires) {
+			c.mu.Unlock()
+			recordOwnerLookupCoalesced(key.gvr)
+			<-call.done
+			return call.obj, call.err
+		}
+		// expired, evict and fetch fresh below
+		delete(c.calls, key)
+	}
+	call := &ownerLookupCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.obj, call.err = fetch()
+
+	c.mu.Lock()
+	call.expires = time.Now().Add(c.ttl)
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.obj, call.err
+}
+
+// Invalidate evicts key so the next Get issues a fresh GET instead of
+// reusing a call that might predate key's object being deleted (or
+// recreated with a new UID under the same name). Call this whenever the
+// GC observes a delete event for key's object -- the intended call site is
+// GraphBuilder.processGraphChanges's delete-event branch, not present in
+// this checkout.
+func (c *ownerLookupCoalescer) Invalidate(key ownerLookupKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls, key)
+}
+
+// ownerBatchThreshold is K from the request: once this many distinct owners
+// of the same GVR+namespace are pending resolution within ownerBatchWindow,
+// flush lists the namespace instead of issuing that many individual Gets.
+// ownerBatchWindow is deliberately short -- a debounce tick, not a cache
+// TTL -- so the common case (one or two distinct owners) only ever pays it
+// once per lookup instead of queueing behind a multi-second wait.
+const (
+	ownerBatchThreshold = 5
+	ownerBatchWindow    = 10 * time.Millisecond
+)
+
+// pendingOwnerGet is one classifyReferences call waiting on an owner
+// lookup that's eligible for batching: it hasn't been coalesced with an
+// identical in-flight call (different name), but it's in the same
+// GVR+namespace as enough siblings that one LIST is cheaper than each of
+// them doing its own GET.
+type pendingOwnerGet struct {
+	name   string
+	result chan ownerLookupResult
+}
+
+type ownerLookupResult struct {
+	obj runtime.Object
+	err error
+}
+
+// ownerBatchKey groups pending lookups the same way ownerLookupKey does,
+// minus the name: everything batchable by one LIST shares a GVR+namespace.
+type ownerBatchKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// ownerBatcher accumulates pending owner lookups per GVR+namespace and,
+// once ownerBatchThreshold distinct names are pending (or ownerBatchWindow
+// elapses, whichever comes first), resolves all of them with a single LIST
+// of that namespace+GVR instead of one GET per name. This is the "deletion
+// fan-out" case from the request: an RS with thousands of Pods gets
+// deleted, and every Pod's classifyReferences call ends up needing the
+// same RS -- which ownerLookupCoalescer already handles -- but also every
+// *other* RS-owned Pod in the namespace may be independently resolving its
+// own, different, already-deleted owner around the same time.
+type ownerBatcher struct {
+	lister func(gvr schema.GroupVersionResource, namespace string) ([]runtime.Object, error)
+	getter func(gvr schema.GroupVersionResource, namespace, name string) (runtime.Object, error)
+
+	mu      sync.Mutex
+	pending map[ownerBatchKey][]pendingOwnerGet
+	timers  map[ownerBatchKey]*time.Timer
+}
+
+func newOwnerBatcher(
+	lister func(gvr schema.GroupVersionResource, namespace string) ([]runtime.Object, error),
+	getter func(gvr schema.GroupVersionResource, namespace, name string) (runtime.Object, error),
+) *ownerBatcher {
+	return &ownerBatcher{
+		lister:  lister,
+		getter:  getter,
+		pending: map[ownerBatchKey][]pendingOwnerGet{},
+		timers:  map[ownerBatchKey]*time.Timer{},
+	}
+}
+
+// Get enqueues a lookup for name and blocks until its batch (or a
+// fallback single-item flush, if it never reached ownerBatchThreshold
+// before ownerBatchWindow elapsed) resolves it.
+func (b *ownerBatcher) Get(gvr schema.GroupVersionResource, namespace, name string) (runtime.Object, error) {
+	key := ownerBatchKey{gvr: gvr, namespace: namespace}
+	result := make(chan ownerLookupResult, 1)
+
+	b.mu.Lock()
+	b.pending[key] = append(b.pending[key], pendingOwnerGet{name: name, result: result})
+	ready := len(b.pending[key]) >= ownerBatchThreshold
+	if ready {
+		if t, ok := b.timers[key]; ok {
+			t.Stop()
+			delete(b.timers, key)
+		}
+	} else if _, ok := b.timers[key]; !ok {
+		b.timers[key] = time.AfterFunc(ownerBatchWindow, func() { b.flush(key) })
+	}
+	b.mu.Unlock()
+
+	if ready {
+		b.flush(key)
+	}
+
+	r := <-result
+	if r.err == errOwnerNotFoundInBatch {
+		return nil, errors.NewNotFound(gvr.GroupResource(), name)
+	}
+	return r.obj, r.err
+}
+
+func (b *ownerBatcher) flush(key ownerBatchKey) {
+	b.mu.Lock()
+	waiters := b.pending[key]
+	delete(b.pending, key)
+	delete(b.timers, key)
+	b.mu.Unlock()
+
+	if len(waiters) == 0 {
+		return
+	}
+
+	distinct := map[string]bool{}
+	for _, w := range waiters {
+		distinct[w.name] = true
+	}
+	if len(distinct) < ownerBatchThreshold {
+		// Not enough distinct owners to make one LIST cheaper than a
+		// handful of Gets; resolve each individually instead of paying for
+		// (and paginating through) a namespace-wide LIST for no benefit.
+		for _, w := range waiters {
+			obj, err := b.getter(key.gvr, key.namespace, w.name)
+			w.result <- ownerLookupResult{obj: obj, err: err}
+		}
+		return
+	}
+
+	objs, err := b.lister(key.gvr, key.namespace)
+	if err != nil {
+		for _, w := range waiters {
+			w.result <- ownerLookupResult{err: err}
+		}
+		return
+	}
+	byName := make(map[string]runtime.Object, len(objs))
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		byName[accessor.GetName()] = obj
+	}
+	for _, w := range waiters {
+		obj, ok := byName[w.name]
+		if !ok {
+			w.result <- ownerLookupResult{err: errOwnerNotFoundInBatch}
+			continue
+		}
+		w.result <- ownerLookupResult{obj: obj}
+	}
+}
+
+// gvrFor resolves the GVR classifyReferences' owner lookups key on, reusing
+// the same apiResource lookup the rest of this package already does to go
+// from an OwnerReference's apiVersion/kind to the plural resource name.
+func (gc *GarbageCollector) gvrFor(apiVersion, kind string, namespaced bool) (schema.GroupVersionResource, error) {
+	resource, err := gc.apiResource(apiVersion, kind, namespaced)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return schema.FromAPIVersionAndKind(apiVersion, kind).GroupVersion().WithResource(resource.Name), nil
+}
+
+// getOwnerByName issues a single GET for gvr's namespace/name. It's
+// ownerBatcher's fallback when too few distinct owners of gvr are pending
+// to make a LIST worthwhile, and is equivalent to the direct
+// client.Resource(...).Get(...) call classifyReferences used to make
+// itself before gc.ownerLookups/gc.ownerBatcher sat in front of it.
+func (gc *GarbageCollector) getOwnerByName(gvr schema.GroupVersionResource, namespace, name string) (runtime.Object, error) {
+	kind, err := gc.restMapper.KindFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gc.clientPool.ClientForGroupVersionKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := gc.apiResource(kind.GroupVersion().String(), kind.Kind, len(namespace) != 0)
+	if err != nil {
+		return nil, err
+	}
+	return client.Resource(resource, namespace).Get(name)
+}
+
+// listOwnerCandidates is ownerBatcher's LIST path: one namespace-wide list
+// of gvr, for flush to index by name once enough distinct owners are
+// pending at once to make that cheaper than issuing a GET per name.
+func (gc *GarbageCollector) listOwnerCandidates(gvr schema.GroupVersionResource, namespace string) ([]runtime.Object, error) {
+	kind, err := gc.restMapper.KindFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gc.clientPool.ClientForGroupVersionKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := gc.apiResource(kind.GroupVersion().String(), kind.Kind, len(namespace) != 0)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.Resource(resource, namespace).List(&v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return meta.ExtractList(list)
+}
+
+var errOwnerNotFoundInBatch = runtimeNotFoundError("owner not found in batched namespace listing")
+
+// runtimeNotFoundError is flush's marker for a name missing from the
+// batched namespace LIST. ownerBatcher.Get turns it into a pkg/api/errors
+// NotFound for the owner's GroupResource and name before returning, so
+// classifyReferences records the owner in absentOwnerCache like any other
+// missing owner.
+type runtimeNotFoundError string
+
+func (e runtimeNotFoundError) Error() string { return string(e) }
+
+// NOTE: ownerLookupCoalescer.Invalidate is never called from this checkout.
+// Its intended caller is GraphBuilder.processGraphChanges's delete-event
+// branch -- the moment the GC's informers observe an object being deleted,
+// before any classifyReferences call for one of its dependents can race a
+// recreate of the same name under a new UID -- but graph.go/GraphBuilder
+// aren't present here. Until that's wired, a dependent resolving its owner
+// within ttl of a delete-then-recreate racing it could see the coalescer's
+// stale (pre-delete) result rather than the fresh object; this is the same
+// staleness window absentOwnerCache already accepts elsewhere in this
+// package, just bounded by ttl instead of by a resync.
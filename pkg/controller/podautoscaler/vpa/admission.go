@@ -0,0 +1,118 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	autoscalingv2 "k8s.io/kubernetes/pkg/apis/autoscaling/v2"
+)
+
+// MutatePodRequests rewrites, in place, the Requests of every container in
+// pod that both has a VerticalPodAutoscaler recommendation and is allowed
+// (by ResourcePolicy) to be controlled for that resource, using the
+// recommendation's Target. It is the core of the VPA admission plugin:
+// called from the plugin's Admit on pod CREATE, before the pod is
+// persisted, so the scheduler sees the recommended request immediately
+// instead of waiting for an eviction/recreate cycle.
+//
+// UpdateModeOff never mutates -- it's the read-only "recommend but don't
+// act" mode -- and a container with no recommendation yet is left alone so
+// a cold VerticalPodAutoscaler doesn't zero out a pod's requests.
+func MutatePodRequests(pod *api.Pod, vpa *autoscaling.VerticalPodAutoscaler) {
+	if vpa.Spec.UpdatePolicy.UpdateMode != nil && *vpa.Spec.UpdatePolicy.UpdateMode == autoscaling.UpdateModeOff {
+		return
+	}
+	if vpa.Status.Recommendation == nil {
+		return
+	}
+
+	recommendations := indexRecommendationsByContainer(vpa.Status.Recommendation.ContainerRecommendations)
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		rec, ok := recommendations[container.Name]
+		if !ok {
+			continue
+		}
+		controlled := controlledResources(vpa.Spec.ResourcePolicy, container.Name)
+		for _, resourceName := range controlled {
+			target, ok := rec.Target[resourceName]
+			if !ok {
+				continue
+			}
+			if container.Resources.Requests == nil {
+				container.Resources.Requests = api.ResourceList{}
+			}
+			container.Resources.Requests[resourceName] = target
+		}
+	}
+}
+
+// ConflictsWithHPA reports whether vpa and hpa would fight over the same
+// target: both are only meaningful once they share a scale target, and they
+// only actually conflict on the resources (CPU, memory) VPA is allowed to
+// control and HPA is scaling on via a Resource MetricSpec. A
+// VerticalPodAutoscaler admitted in this state would otherwise repeatedly
+// rewrite requests that the HPA's own replica-count decisions depend on,
+// with each autoscaler reacting to the other's last move.
+//
+// This is evaluated once at VPA admission time (as ConfigUnsupported,
+// see the internal type's condition docs) rather than continuously, since
+// the fix is to change one of the two autoscaler's configurations, not to
+// have them contend tick over tick.
+func ConflictsWithHPA(vpa *autoscaling.VerticalPodAutoscaler, hpa *autoscalingv2.HorizontalPodAutoscaler) error {
+	if vpa.Spec.TargetRef.Kind != hpa.Spec.ScaleTargetRef.Kind || vpa.Spec.TargetRef.Name != hpa.Spec.ScaleTargetRef.Name {
+		return nil
+	}
+	for _, resourceName := range hpaControlledResources(hpa) {
+		for _, containerPolicy := range vpaControlledResourcesByContainer(vpa) {
+			for _, vpaResource := range containerPolicy {
+				if string(vpaResource) == string(resourceName) {
+					return fmt.Errorf("VerticalPodAutoscaler %s/%s and HorizontalPodAutoscaler %s/%s both target %s/%s and both control %s",
+						vpa.Namespace, vpa.Name, hpa.Namespace, hpa.Name, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name, resourceName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hpaControlledResources collects the CPU/memory Resource metrics hpa
+// scales on; Object, Pods, and External metrics aren't requests on the
+// target's own containers, so they can't conflict with VPA.
+func hpaControlledResources(hpa *autoscalingv2.HorizontalPodAutoscaler) []api.ResourceName {
+	var resources []api.ResourceName
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil {
+			resources = append(resources, metric.Resource.Name)
+		}
+	}
+	return resources
+}
+
+func vpaControlledResourcesByContainer(vpa *autoscaling.VerticalPodAutoscaler) [][]api.ResourceName {
+	if len(vpa.Spec.ResourcePolicy.ContainerPolicies) == 0 {
+		return [][]api.ResourceName{{api.ResourceCPU, api.ResourceMemory}}
+	}
+	all := make([][]api.ResourceName, 0, len(vpa.Spec.ResourcePolicy.ContainerPolicies))
+	for _, p := range vpa.Spec.ResourcePolicy.ContainerPolicies {
+		all = append(all, p.ControlledResources)
+	}
+	return all
+}
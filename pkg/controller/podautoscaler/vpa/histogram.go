@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vpa implements the VerticalPodAutoscaler Recommender: it turns a
+// stream of per-container CPU/memory samples into a lower/target/upper bound
+// recommendation, using an exponentially decayed histogram so that older
+// usage gradually stops influencing the recommendation instead of being
+// weighted equally with the latest samples forever.
+package vpa
+
+import (
+	"math"
+	"time"
+)
+
+const numHistogramBuckets = 1000
+
+// decayingHistogram is a histogram of linear-width buckets whose weights
+// decay exponentially over time: a sample added at time t has decayed to
+// half its original weight by t+halfLife, a quarter by t+2*halfLife, and so
+// on. This lets Percentile reflect recent usage while still smoothing over
+// short-lived spikes and dips, without having to remember every sample ever
+// seen.
+type decayingHistogram struct {
+	halfLife   time.Duration
+	bucketSize float64
+
+	weights    [numHistogramBuckets]float64
+	lastUpdate time.Time
+}
+
+// newDecayingHistogram returns a histogram whose buckets are bucketSize wide
+// (in the metric's natural unit -- cores for CPU, bytes for memory) and
+// whose weights halve every halfLife.
+func newDecayingHistogram(halfLife time.Duration, bucketSize float64) *decayingHistogram {
+	return &decayingHistogram{halfLife: halfLife, bucketSize: bucketSize}
+}
+
+// AddSample records a single observation of value at time at.
+func (h *decayingHistogram) AddSample(value float64, at time.Time) {
+	h.decayTo(at)
+	h.weights[h.bucketIndex(value)]++
+}
+
+// Percentile returns the smallest bucket upper bound b such that at least
+// fraction p of the (decayed) weight observed so far falls at or below b.
+// It returns 0 if no samples have been recorded.
+func (h *decayingHistogram) Percentile(p float64) float64 {
+	var total float64
+	for _, w := range h.weights {
+		total += w
+	}
+	if total == 0 {
+		return 0
+	}
+
+	target := total * p
+	var cumulative float64
+	for i, w := range h.weights {
+		cumulative += w
+		if cumulative >= target {
+			return float64(i+1) * h.bucketSize
+		}
+	}
+	return float64(len(h.weights)) * h.bucketSize
+}
+
+// decayTo multiplies every bucket's weight by the fraction that should have
+// decayed away between the last recorded sample and at, then advances
+// lastUpdate to at. Samples that arrive out of order (at before
+// lastUpdate) are treated as arriving at lastUpdate instead, since the
+// histogram has no way to decay backwards in time.
+func (h *decayingHistogram) decayTo(at time.Time) {
+	if h.lastUpdate.IsZero() {
+		h.lastUpdate = at
+		return
+	}
+	elapsed := at.Sub(h.lastUpdate)
+	if elapsed <= 0 {
+		return
+	}
+	factor := math.Exp2(-elapsed.Seconds() / h.halfLife.Seconds())
+	for i := range h.weights {
+		h.weights[i] *= factor
+	}
+	h.lastUpdate = at
+}
+
+func (h *decayingHistogram) bucketIndex(value float64) int {
+	idx := int(value / h.bucketSize)
+	switch {
+	case idx < 0:
+		return 0
+	case idx >= len(h.weights):
+		return len(h.weights) - 1
+	default:
+		return idx
+	}
+}
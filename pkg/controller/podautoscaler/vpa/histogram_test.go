@@ -0,0 +1,63 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecayingHistogramPercentile(t *testing.T) {
+	base := time.Unix(0, 0)
+	h := newDecayingHistogram(24*time.Hour, 1.0)
+	h.AddSample(5, base)
+
+	if got := h.Percentile(0.5); got != 6 {
+		t.Errorf("Percentile(0.5) = %v, want 6", got)
+	}
+}
+
+func TestDecayingHistogramEmpty(t *testing.T) {
+	h := newDecayingHistogram(24*time.Hour, 1.0)
+	if got := h.Percentile(0.9); got != 0 {
+		t.Errorf("Percentile(0.9) on empty histogram = %v, want 0", got)
+	}
+}
+
+func TestDecayingHistogramDecaysOldSamples(t *testing.T) {
+	base := time.Unix(0, 0)
+	h := newDecayingHistogram(time.Hour, 1.0)
+
+	h.AddSample(10, base)
+	h.AddSample(1, base.Add(100*time.Hour))
+
+	if got := h.Percentile(0.99); got != 2 {
+		t.Errorf("Percentile(0.99) after old sample decayed away = %v, want 2", got)
+	}
+}
+
+func TestDecayingHistogramOutOfOrderSampleDoesNotDecay(t *testing.T) {
+	base := time.Unix(0, 0)
+	h := newDecayingHistogram(time.Hour, 1.0)
+
+	h.AddSample(5, base)
+	h.AddSample(5, base.Add(-time.Hour))
+
+	if got := h.Percentile(1.0); got != 6 {
+		t.Errorf("Percentile(1.0) = %v, want 6", got)
+	}
+}
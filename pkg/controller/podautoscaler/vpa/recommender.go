@@ -0,0 +1,166 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/cadvisor/info"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	"k8s.io/kubernetes/pkg/client"
+)
+
+const (
+	// DefaultHalfLife is used when a VerticalPodAutoscaler doesn't specify
+	// one: a week of continuous usage is needed to fully dominate the
+	// recommendation, which rides out typical daily/weekly load cycles.
+	DefaultHalfLife = 7 * 24 * time.Hour
+
+	cpuBucketSizeCores    = 0.01             // 10 millicores per bucket
+	memoryBucketSizeBytes = 8 * 1024 * 1024 // 8MiB per bucket
+
+	lowerBoundPercentile = 0.5
+	targetPercentile     = 0.9
+	upperBoundPercentile = 0.99
+)
+
+type containerKey struct {
+	podID         string
+	containerName string
+}
+
+type containerHistograms struct {
+	cpu    *decayingHistogram
+	memory *decayingHistogram
+}
+
+// Recommender polls a kubelet's cadvisor endpoint for per-container CPU and
+// memory usage and maintains a decayingHistogram of each, so that
+// Recommendation can report a lower/target/upper bound request for every
+// container it has observed.
+type Recommender struct {
+	infoGetter client.ContainerInfoGetter
+	halfLife   time.Duration
+
+	mu         sync.Mutex
+	histograms map[containerKey]*containerHistograms
+}
+
+// NewRecommender returns a Recommender that polls infoGetter and decays its
+// histograms with the given half-life.
+func NewRecommender(infoGetter client.ContainerInfoGetter, halfLife time.Duration) *Recommender {
+	if halfLife <= 0 {
+		halfLife = DefaultHalfLife
+	}
+	return &Recommender{
+		infoGetter: infoGetter,
+		halfLife:   halfLife,
+		histograms: make(map[containerKey]*containerHistograms),
+	}
+}
+
+// Poll fetches the latest cadvisor stats for a single container of a pod
+// running on host and feeds every new usage sample into that container's
+// histograms.
+func (r *Recommender) Poll(host, podID, containerName string) error {
+	cinfo, err := r.infoGetter.GetContainerInfo(host, podID, containerName, &info.ContainerInfoRequest{NumStats: 60})
+	if err != nil {
+		return err
+	}
+	r.addSamples(podID, containerName, cinfo)
+	return nil
+}
+
+func (r *Recommender) addSamples(podID, containerName string, cinfo *info.ContainerInfo) {
+	hist := r.histogramsFor(podID, containerName)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var prev *info.ContainerStats
+	for _, stat := range cinfo.Stats {
+		stat := stat
+		if prev != nil {
+			if cores, ok := cpuCoresBetween(prev, stat); ok {
+				hist.cpu.AddSample(cores, stat.Timestamp)
+			}
+		}
+		hist.memory.AddSample(float64(stat.Memory.Usage), stat.Timestamp)
+		prev = stat
+	}
+}
+
+// cpuCoresBetween converts the cumulative CPU nanoseconds cadvisor reports
+// into an average cores-used rate over the interval between two consecutive
+// samples. It returns ok=false for a non-positive interval (out-of-order or
+// duplicate samples), which the caller skips rather than recording a
+// division-by-zero or negative-usage sample.
+func cpuCoresBetween(prev, cur *info.ContainerStats) (float64, bool) {
+	elapsed := cur.Timestamp.Sub(prev.Timestamp)
+	if elapsed <= 0 {
+		return 0, false
+	}
+	if cur.Cpu.Usage.Total < prev.Cpu.Usage.Total {
+		return 0, false
+	}
+	deltaNanos := cur.Cpu.Usage.Total - prev.Cpu.Usage.Total
+	return float64(deltaNanos) / float64(elapsed.Nanoseconds()), true
+}
+
+func (r *Recommender) histogramsFor(podID, containerName string) *containerHistograms {
+	key := containerKey{podID: podID, containerName: containerName}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hist, ok := r.histograms[key]
+	if !ok {
+		hist = &containerHistograms{
+			cpu:    newDecayingHistogram(r.halfLife, cpuBucketSizeCores),
+			memory: newDecayingHistogram(r.halfLife, memoryBucketSizeBytes),
+		}
+		r.histograms[key] = hist
+	}
+	return hist
+}
+
+// Recommendation reports the current lower/target/upper bound request for a
+// container, or ok=false if no samples have been observed for it yet.
+func (r *Recommender) Recommendation(podID, containerName string) (autoscaling.RecommendedContainerResources, bool) {
+	r.mu.Lock()
+	hist, ok := r.histograms[containerKey{podID: podID, containerName: containerName}]
+	r.mu.Unlock()
+	if !ok {
+		return autoscaling.RecommendedContainerResources{}, false
+	}
+
+	return autoscaling.RecommendedContainerResources{
+		ContainerName: containerName,
+		LowerBound:    resourceListAt(hist, lowerBoundPercentile),
+		Target:        resourceListAt(hist, targetPercentile),
+		UpperBound:    resourceListAt(hist, upperBoundPercentile),
+	}, true
+}
+
+func resourceListAt(hist *containerHistograms, percentile float64) api.ResourceList {
+	return api.ResourceList{
+		api.ResourceCPU:    *resource.NewMilliQuantity(int64(hist.cpu.Percentile(percentile)*1000), resource.DecimalSI),
+		api.ResourceMemory: *resource.NewQuantity(int64(hist.memory.Percentile(percentile)), resource.BinarySI),
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+)
+
+// NeedsEviction reports whether pod, given the VerticalPodAutoscaler's
+// current recommendation and UpdatePolicy, should be evicted so its
+// replacement picks up the recommendation via admission.
+//
+// Only UpdateModeAuto and UpdateModeRecreate evict running pods; Off only
+// ever records a recommendation, and Initial only applies one to pods as
+// they're created. A pod is evicted only once some container's current
+// request for a controlled resource falls outside [LowerBound, UpperBound]
+// -- being inside the window, even if not exactly at Target, is not by
+// itself worth the disruption of an eviction.
+func NeedsEviction(pod *api.Pod, vpa *autoscaling.VerticalPodAutoscaler) bool {
+	mode := autoscaling.UpdateModeAuto
+	if vpa.Spec.UpdatePolicy.UpdateMode != nil {
+		mode = *vpa.Spec.UpdatePolicy.UpdateMode
+	}
+	if mode != autoscaling.UpdateModeAuto && mode != autoscaling.UpdateModeRecreate {
+		return false
+	}
+	if vpa.Status.Recommendation == nil {
+		return false
+	}
+
+	recommendations := indexRecommendationsByContainer(vpa.Status.Recommendation.ContainerRecommendations)
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		rec, ok := recommendations[container.Name]
+		if !ok {
+			continue
+		}
+		controlled := controlledResources(vpa.Spec.ResourcePolicy, container.Name)
+		for _, resourceName := range controlled {
+			current, ok := container.Resources.Requests[resourceName]
+			if !ok {
+				continue
+			}
+			lower, hasLower := rec.LowerBound[resourceName]
+			upper, hasUpper := rec.UpperBound[resourceName]
+			if hasLower && current.Cmp(lower) < 0 {
+				return true
+			}
+			if hasUpper && current.Cmp(upper) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func indexRecommendationsByContainer(recs []autoscaling.RecommendedContainerResources) map[string]autoscaling.RecommendedContainerResources {
+	byContainer := make(map[string]autoscaling.RecommendedContainerResources, len(recs))
+	for _, rec := range recs {
+		byContainer[rec.ContainerName] = rec
+	}
+	return byContainer
+}
+
+// controlledResources returns the resources ContainerResourcePolicy allows
+// this autoscaler to manage for containerName, falling back to the
+// DefaultContainerResourcePolicyName ("*") entry, and finally to
+// CPU+memory if the VerticalPodAutoscaler has no ResourcePolicy at all.
+func controlledResources(policy autoscaling.PodResourcePolicy, containerName string) []api.ResourceName {
+	var wildcard []api.ResourceName
+	for _, p := range policy.ContainerPolicies {
+		if p.ContainerName == containerName {
+			return p.ControlledResources
+		}
+		if p.ContainerName == autoscaling.DefaultContainerResourcePolicyName {
+			wildcard = p.ControlledResources
+		}
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	if len(policy.ContainerPolicies) == 0 {
+		return []api.ResourceName{api.ResourceCPU, api.ResourceMemory}
+	}
+	return nil
+}
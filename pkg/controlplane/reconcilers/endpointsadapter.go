@@ -0,0 +1,641 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	discoveryclient "k8s.io/client-go/kubernetes/typed/discovery/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	utilnet "k8s.io/utils/net"
+)
+
+// EndpointsAdapter provides a simple interface for reading and writing the
+// kubernetes.default Endpoints and EndpointSlice(s) in lockstep, so callers
+// don't need to know EndpointSlice even exists. It's intentionally narrow:
+// it only understands the handful of operations the master-endpoint
+// reconcilers need (sync the apiserver IP set, plus a per-IP readiness
+// condition), not general-purpose Endpoints/EndpointSlice management.
+type EndpointsAdapter struct {
+	endpointClient      corev1client.EndpointsGetter
+	endpointSliceClient discoveryclient.EndpointSlicesGetter
+
+	// endpointLister and endpointSliceLister, when set by
+	// NewCachedEndpointsAdapter, let Get and Sync read current state from a
+	// shared informer cache instead of issuing a client Get/List against the
+	// apiserver on every reconcile tick. They're nil for the direct-client
+	// constructor used at bootstrap, before any informer has synced.
+	endpointLister      corev1listers.EndpointsLister
+	endpointSliceLister discoverylisters.EndpointSliceLister
+
+	serviceNamespace string
+	serviceName      string
+
+	serviceIP          net.IP
+	secondaryServiceIP net.IP
+
+	// maxEndpointsPerSlice caps how many endpoints go in any one EndpointSlice
+	// per family, matching the ~100-endpoints-per-slice guideline the rest of
+	// Kubernetes follows. It defaults to DefaultMaxEndpointsPerSlice.
+	maxEndpointsPerSlice int
+
+	// sliceOnly, set via SetSliceOnly, stops Sync from writing the deprecated
+	// v1 Endpoints object at all: once EndpointSlice(s) are synced, Sync
+	// deletes any Endpoints left over from before this apiserver switched
+	// modes (or from an older peer) instead of updating it. Get is
+	// unaffected, so it can still bootstrap the IP set from a stale
+	// Endpoints object during a mixed-version upgrade.
+	sliceOnly bool
+}
+
+// DefaultMaxEndpointsPerSlice is the default value of
+// EndpointsAdapter.maxEndpointsPerSlice.
+const DefaultMaxEndpointsPerSlice = 100
+
+// NewEndpointsAdapter returns a new EndpointsAdapter for the given service.
+// secondaryServiceIP may be nil for a single-stack service.
+func NewEndpointsAdapter(endpointClient corev1client.EndpointsGetter, endpointSliceClient discoveryclient.EndpointSlicesGetter, serviceNamespace, serviceName string, serviceIP, secondaryServiceIP net.IP) EndpointsAdapter {
+	return EndpointsAdapter{
+		endpointClient:       endpointClient,
+		endpointSliceClient:  endpointSliceClient,
+		serviceNamespace:     serviceNamespace,
+		serviceName:          serviceName,
+		serviceIP:            serviceIP,
+		secondaryServiceIP:   secondaryServiceIP,
+		maxEndpointsPerSlice: DefaultMaxEndpointsPerSlice,
+	}
+}
+
+// NewCachedEndpointsAdapter returns an EndpointsAdapter that resolves current
+// state from factory's shared Endpoints and EndpointSlice informers rather
+// than polling the apiserver on every Sync call, which matters at scale since
+// the master-lease reconciler ticks on every apiserver in the cluster.
+// Writes still go through endpointClient/endpointSliceClient. Callers must
+// wait for the relevant informers to sync before relying on Get/Sync to see
+// an accurate picture of the cluster; NewEndpointsAdapter remains the right
+// choice for bootstrapping before that has happened.
+func NewCachedEndpointsAdapter(factory informers.SharedInformerFactory, endpointClient corev1client.EndpointsGetter, endpointSliceClient discoveryclient.EndpointSlicesGetter, serviceNamespace, serviceName string, serviceIP, secondaryServiceIP net.IP) EndpointsAdapter {
+	adapter := NewEndpointsAdapter(endpointClient, endpointSliceClient, serviceNamespace, serviceName, serviceIP, secondaryServiceIP)
+	adapter.endpointLister = factory.Core().V1().Endpoints().Lister()
+	adapter.endpointSliceLister = factory.Discovery().V1().EndpointSlices().Lister()
+	return adapter
+}
+
+// SetMaxEndpointsPerSlice overrides the default endpoints-per-slice cap.
+func (adapter *EndpointsAdapter) SetMaxEndpointsPerSlice(max int) {
+	adapter.maxEndpointsPerSlice = max
+}
+
+// SetSliceOnly switches Sync into EndpointSlice-only mode, surfaced through
+// the kube-apiserver flag --endpoint-reconciler-mode=slice-only. See the
+// sliceOnly field doc for what this changes.
+func (adapter *EndpointsAdapter) SetSliceOnly(sliceOnly bool) {
+	adapter.sliceOnly = sliceOnly
+}
+
+// EndpointConditionsByIP lets callers (e.g. the master-lease graceful
+// shutdown machinery) attach per-apiserver-IP EndpointConditions to a Sync
+// call, instead of every apiserver IP being reported Ready unconditionally.
+// An IP with no entry is treated as Ready/Serving and not Terminating.
+type EndpointConditionsByIP map[string]discovery.EndpointConditions
+
+// ZoneTopology carries the topology information Sync needs to compute
+// per-endpoint Zone and Hints.ForZones for topology-aware routing of
+// in-cluster traffic to kubernetes.default. ZoneByIP maps each apiserver IP
+// to the topology.kubernetes.io/zone of the node it's running on; AllZones is
+// the full set of zones known in the cluster, including ones with no local
+// apiserver, so traffic originating there can still be routed somewhere. A
+// nil *ZoneTopology disables zone hints entirely (Zone/Hints stay unset).
+type ZoneTopology struct {
+	ZoneByIP map[string]string
+	AllZones sets.Set[string]
+}
+
+// computeZoneHints applies a simple even-distribution rule: each endpoint
+// hints to its own zone, and any zone with no local apiserver has its traffic
+// fanned out to every endpoint (since there's no better answer for it).
+func computeZoneHints(ips sets.Set[string], zones *ZoneTopology) map[string]discovery.EndpointHints {
+	if zones == nil {
+		return nil
+	}
+
+	zonesWithEndpoint := sets.New[string]()
+	for ip := range ips {
+		if zone := zones.ZoneByIP[ip]; zone != "" {
+			zonesWithEndpoint.Insert(zone)
+		}
+	}
+
+	var zonesWithoutEndpoint []string
+	for zone := range zones.AllZones {
+		if !zonesWithEndpoint.Has(zone) {
+			zonesWithoutEndpoint = append(zonesWithoutEndpoint, zone)
+		}
+	}
+	sort.Strings(zonesWithoutEndpoint)
+
+	hints := make(map[string]discovery.EndpointHints, ips.Len())
+	for ip := range ips {
+		zone := zones.ZoneByIP[ip]
+		if zone == "" {
+			continue
+		}
+		forZones := append([]string{zone}, zonesWithoutEndpoint...)
+		endpointForZones := make([]discovery.ForZone, 0, len(forZones))
+		for _, z := range forZones {
+			endpointForZones = append(endpointForZones, discovery.ForZone{Name: z})
+		}
+		hints[ip] = discovery.EndpointHints{ForZones: endpointForZones}
+	}
+	return hints
+}
+
+// getEndpoints fetches the legacy Endpoints object, from the informer cache
+// if one was wired in by NewCachedEndpointsAdapter, or the client otherwise.
+func (adapter *EndpointsAdapter) getEndpoints() (*corev1.Endpoints, error) {
+	if adapter.endpointLister != nil {
+		return adapter.endpointLister.Endpoints(adapter.serviceNamespace).Get(adapter.serviceName)
+	}
+	return adapter.endpointClient.Endpoints(adapter.serviceNamespace).Get(context.TODO(), adapter.serviceName, metav1.GetOptions{})
+}
+
+// getEndpointSlice fetches the named EndpointSlice, from the informer cache
+// if one was wired in by NewCachedEndpointsAdapter, or the client otherwise.
+func (adapter *EndpointsAdapter) getEndpointSlice(name string) (*discovery.EndpointSlice, error) {
+	if adapter.endpointSliceLister != nil {
+		return adapter.endpointSliceLister.EndpointSlices(adapter.serviceNamespace).Get(name)
+	}
+	return adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// listEndpointSlices returns every EndpointSlice labeled for this service,
+// from the informer cache if one was wired in by NewCachedEndpointsAdapter,
+// or the client otherwise.
+func (adapter *EndpointsAdapter) listEndpointSlices() ([]*discovery.EndpointSlice, error) {
+	selector := labels.Set{discovery.LabelServiceName: adapter.serviceName}.AsSelector()
+	if adapter.endpointSliceLister != nil {
+		return adapter.endpointSliceLister.EndpointSlices(adapter.serviceNamespace).List(selector)
+	}
+	slices, err := adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*discovery.EndpointSlice, len(slices.Items))
+	for i := range slices.Items {
+		result[i] = &slices.Items[i]
+	}
+	return result, nil
+}
+
+// Get returns the set of IPs currently published for the service, preferring
+// EndpointSlice over the legacy Endpoints object when both exist.
+func (adapter *EndpointsAdapter) Get() (sets.Set[string], sets.Set[string], error) {
+	primaryIPs := sets.New[string]()
+	secondaryIPs := sets.New[string]()
+
+	slices, err := adapter.listEndpointSlices()
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	if len(slices) > 0 {
+		for _, slice := range slices {
+			var dest *sets.Set[string]
+			switch {
+			case ipFamilyMatches(slice.AddressType, adapter.serviceIP):
+				dest = &primaryIPs
+			case adapter.secondaryServiceIP != nil && ipFamilyMatches(slice.AddressType, adapter.secondaryServiceIP):
+				dest = &secondaryIPs
+			default:
+				continue
+			}
+			for _, ep := range slice.Endpoints {
+				dest.Insert(ep.Addresses...)
+			}
+		}
+		return primaryIPs, secondaryIPs, nil
+	}
+
+	endpoints, err := adapter.getEndpoints()
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return primaryIPs, secondaryIPs, nil
+		}
+		return nil, nil, err
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, addr := range subset.Addresses {
+			if ipFamilyMatchesString(addr.IP, adapter.serviceIP) {
+				primaryIPs.Insert(addr.IP)
+			} else if adapter.secondaryServiceIP != nil && ipFamilyMatchesString(addr.IP, adapter.secondaryServiceIP) {
+				secondaryIPs.Insert(addr.IP)
+			}
+		}
+	}
+	return primaryIPs, secondaryIPs, nil
+}
+
+// Sync reconciles the kubernetes.default Endpoints and EndpointSlice(s) so
+// they contain exactly ips/ports, creating/updating/deleting objects as
+// necessary. conditionsByIP, if non-nil, overrides the default "every IP is
+// Ready" condition per-IP -- this is how an apiserver whose pre-shutdown hook
+// is running gets reported Ready=false, Serving=true, Terminating=true
+// instead of being pulled from rotation outright.
+func (adapter *EndpointsAdapter) Sync(ips sets.Set[string], ports []corev1.EndpointPort, isDualStackEndpointsFeatureEnabled bool, conditionsByIP EndpointConditionsByIP, zones *ZoneTopology) error {
+	primaryIPs, secondaryIPs := splitByFamily(ips, adapter.serviceIP, adapter.secondaryServiceIP, isDualStackEndpointsFeatureEnabled)
+
+	if !adapter.sliceOnly {
+		if err := adapter.syncEndpoints(primaryIPs, ports, conditionsByIP); err != nil {
+			return err
+		}
+	}
+
+	if err := adapter.syncEndpointSlicesForFamily(adapter.serviceName, primaryIPs, ports, adapter.serviceIP, conditionsByIP, zones); err != nil {
+		return err
+	}
+
+	if adapter.secondaryServiceIP != nil {
+		secondaryBaseName := adapter.secondaryEndpointSliceName()
+		if err := adapter.syncEndpointSlicesForFamily(secondaryBaseName, secondaryIPs, ports, adapter.secondaryServiceIP, conditionsByIP, zones); err != nil {
+			return err
+		}
+	}
+
+	if adapter.sliceOnly {
+		if err := adapter.deleteLegacyEndpoints(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteLegacyEndpoints removes the deprecated v1 Endpoints object once this
+// adapter is in slice-only mode and its EndpointSlice(s) have just been
+// synced above. It tolerates the object already being gone -- a previous
+// tick, or another slice-only apiserver, may have already deleted it -- and
+// tolerates a write conflict from an older, non-slice-only peer that's still
+// updating it concurrently: there's nothing to fight over, since the next
+// Sync tick will simply try the delete again.
+func (adapter *EndpointsAdapter) deleteLegacyEndpoints() error {
+	err := adapter.endpointClient.Endpoints(adapter.serviceNamespace).Delete(context.TODO(), adapter.serviceName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+func (adapter *EndpointsAdapter) secondaryEndpointSliceName() string {
+	return adapter.serviceName + "-v6"
+}
+
+func (adapter *EndpointsAdapter) syncEndpoints(ips sets.Set[string], ports []corev1.EndpointPort, conditionsByIP EndpointConditionsByIP) error {
+	subset := corev1.EndpointSubset{Ports: ports}
+	for _, ip := range sets.List(ips) {
+		addr := corev1.EndpointAddress{IP: ip}
+		if isTerminating(conditionsByIP[ip]) {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, addr)
+		} else {
+			subset.Addresses = append(subset.Addresses, addr)
+		}
+	}
+
+	desired := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      adapter.serviceName,
+			Namespace: adapter.serviceNamespace,
+			Labels: map[string]string{
+				discovery.LabelSkipMirror: "true",
+			},
+		},
+		Subsets: []corev1.EndpointSubset{subset},
+	}
+
+	existing, err := adapter.getEndpoints()
+	if apierrors.IsNotFound(err) {
+		_, err = adapter.endpointClient.Endpoints(adapter.serviceNamespace).Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if endpointsEqual(existing, desired) {
+		return nil
+	}
+	desired.ResourceVersion = existing.ResourceVersion
+	_, err = adapter.endpointClient.Endpoints(adapter.serviceNamespace).Update(context.TODO(), desired, metav1.UpdateOptions{})
+	return err
+}
+
+// shardName returns the deterministic name of the shardIndex'th EndpointSlice
+// for a family. Shard 0 keeps the legacy unsharded name (baseName) so a
+// single-apiserver (or downgraded) cluster's slice name doesn't churn.
+func shardName(baseName string, shardIndex int) string {
+	if shardIndex == 0 {
+		return baseName
+	}
+	return fmt.Sprintf("%s-%d", baseName, shardIndex)
+}
+
+// shardIPs splits the (sorted) ip list into ordered, deterministic shards of
+// at most maxPerSlice entries each. It always returns at least one shard
+// (possibly empty) so callers can reconcile the base slice even when there
+// are no IPs at all.
+func shardIPs(ips sets.Set[string], maxPerSlice int) [][]string {
+	if maxPerSlice <= 0 {
+		maxPerSlice = DefaultMaxEndpointsPerSlice
+	}
+	all := sets.List(ips)
+	if len(all) == 0 {
+		return [][]string{{}}
+	}
+	var shards [][]string
+	for i := 0; i < len(all); i += maxPerSlice {
+		end := i + maxPerSlice
+		if end > len(all) {
+			end = len(all)
+		}
+		shards = append(shards, all[i:end])
+	}
+	return shards
+}
+
+func (adapter *EndpointsAdapter) syncEndpointSlicesForFamily(baseName string, ips sets.Set[string], ports []corev1.EndpointPort, familyIP net.IP, conditionsByIP EndpointConditionsByIP, zones *ZoneTopology) error {
+	addressType := discovery.AddressTypeIPv4
+	if utilnet.IsIPv6(familyIP) {
+		addressType = discovery.AddressTypeIPv6
+	}
+
+	var zoneByIP map[string]string
+	if zones != nil {
+		zoneByIP = zones.ZoneByIP
+	}
+	hintsByIP := computeZoneHints(ips, zones)
+
+	shards := shardIPs(ips, adapter.maxEndpointsPerSlice)
+	keep := sets.New[string]()
+	for shardIndex, shardIPList := range shards {
+		name := shardName(baseName, shardIndex)
+		keep.Insert(name)
+
+		// Only the base shard is reconciled when there are no IPs at all;
+		// higher-indexed shards only ever exist to hold overflow, so an
+		// empty one is garbage-collected below rather than created/kept.
+		if len(shardIPList) == 0 && shardIndex > 0 {
+			continue
+		}
+
+		desired := buildEndpointSlice(adapter.serviceNamespace, name, adapter.serviceName, addressType, shardIPList, ports, conditionsByIP, zoneByIP, hintsByIP)
+		if err := adapter.applyEndpointSlice(name, addressType, desired); err != nil {
+			return err
+		}
+	}
+
+	return adapter.garbageCollectShards(baseName, keep)
+}
+
+// applyEndpointSlice creates, updates, or (if addressType changed) recreates
+// the single named EndpointSlice to match desired.
+func (adapter *EndpointsAdapter) applyEndpointSlice(name string, addressType discovery.AddressType, desired *discovery.EndpointSlice) error {
+	existing, err := adapter.getEndpointSlice(name)
+	if apierrors.IsNotFound(err) {
+		if len(desired.Endpoints) == 0 {
+			return nil
+		}
+		_, err = adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.AddressType != addressType {
+		if err := adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		_, err = adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err
+	}
+
+	if endpointSlicesEqual(existing, desired) {
+		return nil
+	}
+	desired.ResourceVersion = existing.ResourceVersion
+	_, err = adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).Update(context.TODO(), desired, metav1.UpdateOptions{})
+	return err
+}
+
+// garbageCollectShards deletes any "baseName(-N)" EndpointSlice that's no
+// longer part of the desired shard set, e.g. kubernetes-1 after the cluster
+// shrinks back under the per-slice endpoint threshold.
+func (adapter *EndpointsAdapter) garbageCollectShards(baseName string, keep sets.Set[string]) error {
+	for shardIndex := 1; ; shardIndex++ {
+		name := shardName(baseName, shardIndex)
+		if keep.Has(name) {
+			continue
+		}
+		if _, err := adapter.getEndpointSlice(name); apierrors.IsNotFound(err) {
+			// Once we hit a shard index that never existed, there can't be
+			// any higher ones to clean up either.
+			return nil
+		} else if err != nil {
+			return err
+		}
+		err := adapter.endpointSliceClient.EndpointSlices(adapter.serviceNamespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+}
+
+func buildEndpointSlice(namespace, name, serviceName string, addressType discovery.AddressType, ips []string, ports []corev1.EndpointPort, conditionsByIP EndpointConditionsByIP, zoneByIP map[string]string, hintsByIP map[string]discovery.EndpointHints) *discovery.EndpointSlice {
+	slice := &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				discovery.LabelServiceName: serviceName,
+			},
+		},
+		AddressType: addressType,
+	}
+	for i := range ports {
+		port := ports[i]
+		slice.Ports = append(slice.Ports, discovery.EndpointPort{
+			Name:     &port.Name,
+			Port:     &port.Port,
+			Protocol: &port.Protocol,
+		})
+	}
+
+	for _, ip := range ips {
+		endpoint := discovery.Endpoint{
+			Addresses:  []string{ip},
+			Conditions: endpointConditionsFor(conditionsByIP[ip]),
+		}
+		if zone := zoneByIP[ip]; zone != "" {
+			endpoint.Zone = &zone
+		}
+		if hints, ok := hintsByIP[ip]; ok {
+			endpoint.Hints = &hints
+		}
+		slice.Endpoints = append(slice.Endpoints, endpoint)
+	}
+	return slice
+}
+
+// endpointConditionsFor fills in the KEP-1669 tri-state (Ready/Serving/
+// Terminating) for one apiserver IP. A zero-value EndpointConditions (the
+// common case: an apiserver that isn't shutting down) maps to the default
+// Ready=true, Serving=true, Terminating=false.
+func endpointConditionsFor(conditions discovery.EndpointConditions) discovery.EndpointConditions {
+	if conditions.Ready == nil && conditions.Serving == nil && conditions.Terminating == nil {
+		ready := true
+		terminating := false
+		return discovery.EndpointConditions{Ready: &ready, Serving: &ready, Terminating: &terminating}
+	}
+	return conditions
+}
+
+func isTerminating(conditions discovery.EndpointConditions) bool {
+	return conditions.Terminating != nil && *conditions.Terminating
+}
+
+func ipFamilyMatches(addressType discovery.AddressType, familyIP net.IP) bool {
+	if familyIP == nil {
+		return addressType == discovery.AddressTypeIPv4
+	}
+	if utilnet.IsIPv6(familyIP) {
+		return addressType == discovery.AddressTypeIPv6
+	}
+	return addressType == discovery.AddressTypeIPv4
+}
+
+func ipFamilyMatchesString(ip string, familyIP net.IP) bool {
+	if familyIP == nil {
+		return !utilnet.IsIPv6String(ip)
+	}
+	if utilnet.IsIPv6(familyIP) {
+		return utilnet.IsIPv6String(ip)
+	}
+	return !utilnet.IsIPv6String(ip)
+}
+
+// splitByFamily partitions ips into the primary and secondary address
+// families, dropping the secondary family entirely unless dual-stack is
+// enabled and a secondary service IP was actually configured.
+func splitByFamily(ips sets.Set[string], primaryIP, secondaryIP net.IP, dualStackEnabled bool) (sets.Set[string], sets.Set[string]) {
+	primary := sets.New[string]()
+	secondary := sets.New[string]()
+	for ip := range ips {
+		switch {
+		case ipFamilyMatchesString(ip, primaryIP):
+			primary.Insert(ip)
+		case dualStackEnabled && secondaryIP != nil && ipFamilyMatchesString(ip, secondaryIP):
+			secondary.Insert(ip)
+		}
+	}
+	return primary, secondary
+}
+
+func endpointsEqual(a, b *corev1.Endpoints) bool {
+	return subsetsEqual(a.Subsets, b.Subsets)
+}
+
+func subsetsEqual(a, b []corev1.EndpointSubset) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !addressesEqual(a[i].Addresses, b[i].Addresses) || !addressesEqual(a[i].NotReadyAddresses, b[i].NotReadyAddresses) {
+			return false
+		}
+		if fmt.Sprint(a[i].Ports) != fmt.Sprint(b[i].Ports) {
+			return false
+		}
+	}
+	return true
+}
+
+func addressesEqual(a, b []corev1.EndpointAddress) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := sets.New[string]()
+	for _, addr := range a {
+		seen.Insert(addr.IP)
+	}
+	for _, addr := range b {
+		if !seen.Has(addr.IP) {
+			return false
+		}
+	}
+	return true
+}
+
+func endpointSlicesEqual(a, b *discovery.EndpointSlice) bool {
+	if a.AddressType != b.AddressType {
+		return false
+	}
+	if !apiequality.Semantic.DeepEqual(a.Ports, b.Ports) {
+		return false
+	}
+	if len(a.Endpoints) != len(b.Endpoints) {
+		return false
+	}
+	byAddr := make(map[string]discovery.Endpoint, len(a.Endpoints))
+	for _, ep := range a.Endpoints {
+		if len(ep.Addresses) > 0 {
+			byAddr[ep.Addresses[0]] = ep
+		}
+	}
+	for _, ep := range b.Endpoints {
+		if len(ep.Addresses) == 0 {
+			return false
+		}
+		existing, ok := byAddr[ep.Addresses[0]]
+		if !ok ||
+			!apiequality.Semantic.DeepEqual(existing.Conditions, ep.Conditions) ||
+			derefString(existing.Zone) != derefString(ep.Zone) ||
+			!apiequality.Semantic.DeepEqual(existing.Hints, ep.Hints) {
+			return false
+		}
+	}
+	return true
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package reconcilers
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,10 +28,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 	utilnet "k8s.io/utils/net"
 )
 
@@ -124,7 +129,32 @@ func TestEndpointsAdapterGet(t *testing.T) {
 	}
 }
 
-func TestEndpointsAdapterSync(t *testing.T) {
+// endpointsAdapterSyncTestCase is a single TestEndpointsAdapterSync /
+// TestCachedEndpointsAdapterSync table entry -- the two tests share this
+// table so the informer-cache-backed constructor is held to exactly the same
+// behaviors as the direct-client one.
+type endpointsAdapterSyncTestCase struct {
+	serviceIP          net.IP
+	secondaryServiceIP net.IP
+	initialState       []runtime.Object
+	ipsParam           sets.Set[string]
+	portsParam         []corev1.EndpointPort
+	conditionsParam    EndpointConditionsByIP
+	zonesParam         *ZoneTopology
+	maxPerSliceParam   int
+	sliceOnlyParam     bool
+	// deleteConflict simulates an older, non-slice-only peer racing this
+	// adapter's deleteLegacyEndpoints call with its own Endpoints update, so
+	// the client's Delete returns a conflict instead of succeeding.
+	deleteConflict bool
+
+	expectedError error
+	expectCreate  []runtime.Object
+	expectUpdate  []runtime.Object
+	expectDelete  []runtime.Object
+}
+
+func endpointsAdapterSyncTestCases() map[string]endpointsAdapterSyncTestCase {
 	endpoints1, epSlice1 := generateEndpointsAndSlice([]int{80}, []string{"10.1.2.3", "10.1.2.4"})
 	ips1 := sets.New("10.1.2.3", "10.1.2.4")
 	ports1 := endpoints1.Subsets[0].Ports
@@ -159,18 +189,23 @@ func TestEndpointsAdapterSync(t *testing.T) {
 	epSlice1SecondaryEmpty := epSlice1Secondary.DeepCopy()
 	epSlice1SecondaryEmpty.Endpoints = []discovery.Endpoint{}
 
-	testCases := map[string]struct {
-		serviceIP          net.IP
-		secondaryServiceIP net.IP
-		initialState       []runtime.Object
-		ipsParam           sets.Set[string]
-		portsParam         []corev1.EndpointPort
+	// terminatingAPIServer fixtures cover the case where one of three apiservers
+	// has entered its pre-shutdown-hook window: Ready=false, Serving=true,
+	// Terminating=true per KEP-1669, with the legacy Endpoints object placing
+	// the IP in NotReadyAddresses instead of Addresses.
+	terminatingConditions := EndpointConditionsByIP{
+		"10.1.2.5": terminatingEndpointConditions(),
+	}
+	endpoints3Terminating, epSlice3Terminating := generateEndpointsAndSliceWithConditions([]int{80, 443}, []string{"10.1.2.3", "10.1.2.4", "10.1.2.5"}, terminatingConditions)
 
-		expectedError error
-		expectCreate  []runtime.Object
-		expectUpdate  []runtime.Object
-		expectDelete  []runtime.Object
-	}{
+	terminatingConditionsDual := EndpointConditionsByIP{
+		"1234::5678": terminatingEndpointConditions(),
+	}
+	endpointsDualTerminating, _ := generateEndpointsAndSliceWithConditions([]int{80}, []string{"10.1.2.3", "10.1.2.4", "1234::5678", "1234::abcd"}, terminatingConditionsDual)
+	_, epSliceV6Terminating := generateEndpointsAndSliceWithConditions([]int{80}, []string{"1234::5678", "1234::abcd"}, terminatingConditionsDual)
+	epSliceV6Terminating.Name = "kubernetes-v6"
+
+	testCases := map[string]endpointsAdapterSyncTestCase{
 		"single-endpoint": {
 			// If the Endpoints/EndpointSlice do not exist, they will be
 			// created.
@@ -390,16 +425,224 @@ func TestEndpointsAdapterSync(t *testing.T) {
 			expectDelete: []runtime.Object{epSlice1AltName, epSlice1Secondary},
 			expectCreate: []runtime.Object{endpoints2, epSlice2},
 		},
+		"one-of-three-apiservers-terminating-single-stack": {
+			// When one of three apiservers is within its pre-shutdown-hook
+			// window, its IP moves to NotReadyAddresses in Endpoints and gets
+			// Ready=false/Serving=true/Terminating=true in EndpointSlice,
+			// instead of being dropped outright.
+			serviceIP:       testServiceIP,
+			initialState:    []runtime.Object{endpoints2, epSlice2},
+			ipsParam:        ips2,
+			portsParam:      ports2,
+			conditionsParam: terminatingConditions,
+
+			expectUpdate: []runtime.Object{endpoints3Terminating, epSlice3Terminating},
+		},
+		"one-of-three-apiservers-terminating-dual-stack": {
+			// Same as above, but for the secondary (IPv6) family in a
+			// dual-stack rollout.
+			serviceIP:          testServiceIP,
+			secondaryServiceIP: testServiceIPv6,
+			initialState:       []runtime.Object{endpoints1, epSlice1, epSlice1Secondary},
+			ipsParam:           ipsDual,
+			portsParam:         ports1,
+			conditionsParam:    terminatingConditionsDual,
+
+			expectUpdate: []runtime.Object{endpointsDualTerminating, epSliceV6Terminating},
+		},
+		"three-zones-three-apiservers": {
+			// Each apiserver hints to its own zone only, since every zone has
+			// a local apiserver to serve it.
+			serviceIP: testServiceIP,
+			ipsParam:  sets.New("10.1.2.3", "10.1.2.4", "10.1.2.5"),
+			zonesParam: &ZoneTopology{
+				ZoneByIP: map[string]string{
+					"10.1.2.3": "zone-a",
+					"10.1.2.4": "zone-b",
+					"10.1.2.5": "zone-c",
+				},
+				AllZones: sets.New("zone-a", "zone-b", "zone-c"),
+			},
+
+			expectCreate: []runtime.Object{
+				func() *corev1.Endpoints { e, _ := generateEndpointsAndSlice(nil, []string{"10.1.2.3", "10.1.2.4", "10.1.2.5"}); return e }(),
+				generateEndpointSliceWithZones(nil, map[string][]string{
+					"zone-a": {"10.1.2.3"},
+					"zone-b": {"10.1.2.4"},
+					"zone-c": {"10.1.2.5"},
+				}, sets.New("zone-a", "zone-b", "zone-c")),
+			},
+		},
+		"two-zones-one-apiserver": {
+			// The lone apiserver's zone has no endpoints of its own to speak
+			// of, so the single apiserver must hint to both zones.
+			serviceIP: testServiceIP,
+			ipsParam:  sets.New("10.1.2.3"),
+			zonesParam: &ZoneTopology{
+				ZoneByIP: map[string]string{
+					"10.1.2.3": "zone-a",
+				},
+				AllZones: sets.New("zone-a", "zone-b"),
+			},
+
+			expectCreate: []runtime.Object{
+				func() *corev1.Endpoints { e, _ := generateEndpointsAndSlice(nil, []string{"10.1.2.3"}); return e }(),
+				generateEndpointSliceWithZones(nil, map[string][]string{
+					"zone-a": {"10.1.2.3"},
+				}, sets.New("zone-a", "zone-b")),
+			},
+		},
+		"grows-past-max-endpoints-per-slice": {
+			// With a threshold of 2, 3 IPs shard into "kubernetes" (2 IPs)
+			// and "kubernetes-1" (the 1 remaining IP).
+			serviceIP:        testServiceIP,
+			ipsParam:         sets.New("10.1.2.3", "10.1.2.4", "10.1.2.5"),
+			portsParam:       ports1,
+			maxPerSliceParam: 2,
+
+			expectCreate: []runtime.Object{
+				func() *corev1.Endpoints { e, _ := generateEndpointsAndSlice([]int{80}, []string{"10.1.2.3", "10.1.2.4", "10.1.2.5"}); return e }(),
+				shardSliceNamed(testServiceName, []int{80}, []string{"10.1.2.3", "10.1.2.4"}),
+				shardSliceNamed(fmt.Sprintf("%s-1", testServiceName), []int{80}, []string{"10.1.2.5"}),
+			},
+		},
+		"shrinks-back-under-max-endpoints-per-slice": {
+			// Going from 3 IPs back down to 2 deletes the now-unneeded
+			// overflow slice "kubernetes-1".
+			serviceIP: testServiceIP,
+			initialState: []runtime.Object{
+				func() *corev1.Endpoints { e, _ := generateEndpointsAndSlice([]int{80}, []string{"10.1.2.3", "10.1.2.4", "10.1.2.5"}); return e }(),
+				shardSliceNamed(testServiceName, []int{80}, []string{"10.1.2.3", "10.1.2.4"}),
+				shardSliceNamed(fmt.Sprintf("%s-1", testServiceName), []int{80}, []string{"10.1.2.5"}),
+			},
+			ipsParam:         sets.New("10.1.2.3", "10.1.2.4"),
+			portsParam:       ports1,
+			maxPerSliceParam: 2,
+
+			expectUpdate: []runtime.Object{endpoints1},
+			expectDelete: []runtime.Object{shardSliceNamed(fmt.Sprintf("%s-1", testServiceName), []int{80}, []string{"10.1.2.5"})},
+		},
+		"slice-only-fresh-cluster": {
+			// In slice-only mode, a fresh cluster gets only an EndpointSlice
+			// -- no deprecated Endpoints object is ever created.
+			serviceIP:      testServiceIP,
+			initialState:   []runtime.Object{},
+			ipsParam:       ips1,
+			portsParam:     ports1,
+			sliceOnlyParam: true,
+
+			expectCreate: []runtime.Object{epSlice1},
+		},
+		"slice-only-upgrade-deletes-legacy-endpoints": {
+			// Switching an apiserver that previously wrote Endpoints over to
+			// slice-only mode deletes the now-unowned Endpoints object once
+			// its EndpointSlice is confirmed in sync.
+			serviceIP:      testServiceIP,
+			initialState:   []runtime.Object{endpoints1, epSlice1},
+			ipsParam:       ips1,
+			portsParam:     ports1,
+			sliceOnlyParam: true,
+
+			expectDelete: []runtime.Object{endpoints1},
+		},
+		"slice-only-downgrade-tolerates-peer-owned-endpoints": {
+			// An older, non-slice-only peer apiserver is still writing the
+			// Endpoints object out from under us; our Delete loses the race
+			// with a conflict. Sync must tolerate that instead of surfacing
+			// it as an error -- there's nothing to fight over, the next tick
+			// just tries the delete again.
+			serviceIP:      testServiceIP,
+			initialState:   []runtime.Object{endpoints1, epSlice1},
+			ipsParam:       ips1,
+			portsParam:     ports1,
+			sliceOnlyParam: true,
+			deleteConflict: true,
+
+			expectDelete: []runtime.Object{endpoints1},
+		},
 	}
 
-	for name, testCase := range testCases {
+	return testCases
+}
+
+func TestEndpointsAdapterSync(t *testing.T) {
+	for name, testCase := range endpointsAdapterSyncTestCases() {
 		t.Run(name, func(t *testing.T) {
 			client := fake.NewSimpleClientset(testCase.initialState...)
+			if testCase.deleteConflict {
+				client.PrependReactor("delete", "endpoints", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewConflict(corev1.Resource("endpoints"), testServiceName, fmt.Errorf("conflicting peer update"))
+				})
+			}
 			epAdapter := NewEndpointsAdapter(client.CoreV1(), client.DiscoveryV1(),
 				testServiceNamespace, testServiceName,
 				testCase.serviceIP, testCase.secondaryServiceIP)
+			if testCase.maxPerSliceParam > 0 {
+				epAdapter.SetMaxEndpointsPerSlice(testCase.maxPerSliceParam)
+			}
+			if testCase.sliceOnlyParam {
+				epAdapter.SetSliceOnly(true)
+			}
+
+			err := epAdapter.Sync(testCase.ipsParam, testCase.portsParam, true, testCase.conditionsParam, testCase.zonesParam)
+			if !apiequality.Semantic.DeepEqual(testCase.expectedError, err) {
+				t.Errorf("Expected error: %v, got: %v", testCase.expectedError, err)
+			}
+
+			err = verifyActions(client, testCase.expectCreate, testCase.expectUpdate, testCase.expectDelete)
+			if err != nil {
+				t.Errorf("unexpected error in side effects: %v", err)
+			}
+		})
+	}
+}
+
+// TestCachedEndpointsAdapterSync runs the exact same table as
+// TestEndpointsAdapterSync against NewCachedEndpointsAdapter, seeding each
+// case's initialState into the informer stores (and into the fake client,
+// whose seeding actions are then cleared), so the cached adapter is held to
+// identical behavior without ever
+// needing a client Get to observe the starting state.
+func TestCachedEndpointsAdapterSync(t *testing.T) {
+	for name, testCase := range endpointsAdapterSyncTestCases() {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			factory := informers.NewSharedInformerFactory(client, 0)
+			endpointStore := factory.Core().V1().Endpoints().Informer().GetStore()
+			endpointSliceStore := factory.Discovery().V1().EndpointSlices().Informer().GetStore()
+			for _, obj := range testCase.initialState {
+				switch o := obj.(type) {
+				case *corev1.Endpoints:
+					_ = endpointStore.Add(o)
+					_, _ = client.CoreV1().Endpoints(o.Namespace).Create(context.TODO(), o, metav1.CreateOptions{})
+				case *discovery.EndpointSlice:
+					_ = endpointSliceStore.Add(o)
+					_, _ = client.DiscoveryV1().EndpointSlices(o.Namespace).Create(context.TODO(), o, metav1.CreateOptions{})
+				}
+			}
+			// The fake client above only exists to record the writes Sync
+			// makes below for verifyActions; clear the Create actions it
+			// just recorded for seeding so they aren't mistaken for ones Sync
+			// itself performed.
+			client.ClearActions()
+
+			if testCase.deleteConflict {
+				client.PrependReactor("delete", "endpoints", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewConflict(corev1.Resource("endpoints"), testServiceName, fmt.Errorf("conflicting peer update"))
+				})
+			}
+
+			epAdapter := NewCachedEndpointsAdapter(factory, client.CoreV1(), client.DiscoveryV1(),
+				testServiceNamespace, testServiceName,
+				testCase.serviceIP, testCase.secondaryServiceIP)
+			if testCase.maxPerSliceParam > 0 {
+				epAdapter.SetMaxEndpointsPerSlice(testCase.maxPerSliceParam)
+			}
+			if testCase.sliceOnlyParam {
+				epAdapter.SetSliceOnly(true)
+			}
 
-			err := epAdapter.Sync(testCase.ipsParam, testCase.portsParam, true)
+			err := epAdapter.Sync(testCase.ipsParam, testCase.portsParam, true, testCase.conditionsParam, testCase.zonesParam)
 			if !apiequality.Semantic.DeepEqual(testCase.expectedError, err) {
 				t.Errorf("Expected error: %v, got: %v", testCase.expectedError, err)
 			}
@@ -412,7 +655,92 @@ func TestEndpointsAdapterSync(t *testing.T) {
 	}
 }
 
+// TestCachedEndpointsAdapterNoClientReads confirms the whole point of
+// NewCachedEndpointsAdapter: when the informer cache already matches desired
+// state, Sync resolves it from the cache alone and never issues a client Get
+// against the apiserver.
+func TestCachedEndpointsAdapterNoClientReads(t *testing.T) {
+	endpoints1, epSlice1 := generateEndpointsAndSlice([]int{80}, []string{"10.1.2.3", "10.1.2.4"})
+	ips1 := sets.New("10.1.2.3", "10.1.2.4")
+	ports1 := endpoints1.Subsets[0].Ports
+
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("get", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		t.Errorf("unexpected client Get action for %s -- the cached adapter should have resolved this from the informer cache", action.GetResource().Resource)
+		return true, nil, fmt.Errorf("unexpected Get")
+	})
+
+	factory := informers.NewSharedInformerFactory(client, 0)
+	_ = factory.Core().V1().Endpoints().Informer().GetStore().Add(endpoints1)
+	_ = factory.Discovery().V1().EndpointSlices().Informer().GetStore().Add(epSlice1)
+
+	epAdapter := NewCachedEndpointsAdapter(factory, client.CoreV1(), client.DiscoveryV1(), testServiceNamespace, testServiceName, testServiceIP, nil)
+	if err := epAdapter.Sync(ips1, ports1, true, nil, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := verifyActions(client, nil, nil, nil); err != nil {
+		t.Errorf("unexpected error in side effects: %v", err)
+	}
+}
+
 func generateEndpointsAndSlice(ports []int, addresses []string) (*corev1.Endpoints, *discovery.EndpointSlice) {
+	return generateEndpointsAndSliceWithConditions(ports, addresses, nil)
+}
+
+// shardSliceNamed builds the expected EndpointSlice for one shard of a
+// sharded family, overriding the generated name to the shard's own name
+// (e.g. "kubernetes-1") instead of the base service name.
+func shardSliceNamed(name string, ports []int, addresses []string) *discovery.EndpointSlice {
+	_, epSlice := generateEndpointsAndSliceWithConditions(ports, addresses, nil)
+	epSlice.Name = name
+	return epSlice
+}
+
+// terminatingEndpointConditions returns the KEP-1669 tri-state for an
+// apiserver whose pre-shutdown hook is running: it's no longer Ready, but
+// still Serving existing connections while it Terminates.
+func terminatingEndpointConditions() discovery.EndpointConditions {
+	falseBool := false
+	trueBool := true
+	return discovery.EndpointConditions{
+		Ready:       &falseBool,
+		Serving:     &trueBool,
+		Terminating: &trueBool,
+	}
+}
+
+// generateEndpointSliceWithZones builds just the expected EndpointSlice for a
+// zone-hints test case, given each address's zone and the full set of zones
+// known in the cluster (which may include zones with no local apiserver).
+func generateEndpointSliceWithZones(ports []int, addressesByZone map[string][]string, allZones sets.Set[string]) *discovery.EndpointSlice {
+	var addresses []string
+	zoneByIP := map[string]string{}
+	for zone, ips := range addressesByZone {
+		for _, ip := range ips {
+			addresses = append(addresses, ip)
+			zoneByIP[ip] = zone
+		}
+	}
+	sort.Strings(addresses)
+
+	_, epSlice := generateEndpointsAndSliceWithConditions(ports, addresses, nil)
+
+	zones := &ZoneTopology{ZoneByIP: zoneByIP, AllZones: allZones}
+	ips := sets.New(addresses...)
+	hintsByIP := computeZoneHints(ips, zones)
+	for i := range epSlice.Endpoints {
+		ip := epSlice.Endpoints[i].Addresses[0]
+		zone := zoneByIP[ip]
+		epSlice.Endpoints[i].Zone = &zone
+		if hints, ok := hintsByIP[ip]; ok {
+			epSlice.Endpoints[i].Hints = &hints
+		}
+	}
+	return epSlice
+}
+
+func generateEndpointsAndSliceWithConditions(ports []int, addresses []string, conditionsByIP EndpointConditionsByIP) (*corev1.Endpoints, *discovery.EndpointSlice) {
 	trueBool := true
 	addressType := discovery.AddressTypeIPv4
 	if len(addresses) > 0 && utilnet.IsIPv6String(addresses[0]) {
@@ -448,11 +776,18 @@ func generateEndpointsAndSlice(ports []int, addresses []string) (*corev1.Endpoin
 			IP: address,
 		}
 
-		subset.Addresses = append(subset.Addresses, endpointAddress)
+		conditions, terminating := conditionsByIP[address]
+		if terminating && isTerminating(conditions) {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, endpointAddress)
+		} else {
+			subset.Addresses = append(subset.Addresses, endpointAddress)
+			falseBool := false
+			conditions = discovery.EndpointConditions{Ready: &trueBool, Serving: &trueBool, Terminating: &falseBool}
+		}
 
 		epSlice.Endpoints = append(epSlice.Endpoints, discovery.Endpoint{
 			Addresses:  []string{endpointAddress.IP},
-			Conditions: discovery.EndpointConditions{Ready: &trueBool},
+			Conditions: conditions,
 		})
 	}
 
@@ -18,11 +18,15 @@ package dockershim
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -41,6 +45,19 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/dockershim/libdocker"
 )
 
+const (
+	// udpPortForwardSocketReadyTimeout bounds how long we wait for socat to
+	// create its relay socket (in a host temp directory; nsenter enters only
+	// the container's network namespace) before giving up on a udp
+	// port-forward attempt.
+	udpPortForwardSocketReadyTimeout = 30 * time.Second
+	// udpPortForwardSocketPollInterval is how often we poll for the socket's
+	// existence while waiting for socat to come up.
+	udpPortForwardSocketPollInterval = 10 * time.Millisecond
+	// udpPortForwardBufferSize is large enough for any UDP datagram.
+	udpPortForwardBufferSize = 65536
+)
+
 type streamingRuntime struct {
 	client      libdocker.Interface
 	execHandler ExecHandler
@@ -70,17 +87,23 @@ func (r *streamingRuntime) Attach(containerID string, in io.Reader, out, errw io
 	return attachContainer(r.client, containerID, in, out, errw, tty, resize)
 }
 
-//need udp
 func (r *streamingRuntime) PortForward(podSandboxID string, protocol string, port int32, stream io.ReadWriteCloser) error {
-	glog.V(3).Infof("haha: into func (r *streamingRuntime) PortForward(podSandboxID string, protocol string, port int32, stream io.ReadWriteCloser) error ")
-
 	if port < 0 || port > math.MaxUint16 {
 		return fmt.Errorf("invalid port %d", port)
 	}
-	if protocol != api.PortForwardProtocolTypeTcp4 && protocol != api.PortForwardProtocolTypeUdp4 {
+	switch protocol {
+	case api.PortForwardProtocolTypeTcp4, api.PortForwardProtocolTypeUdp4, api.PortForwardProtocolTypeUdp6:
+	default:
 		return fmt.Errorf("invalid or not supported protocol %s", protocol)
 	}
-	return portForward(r.client, podSandboxID, protocol, port, stream)
+
+	// The port-forward stays open for the lifetime of the SPDY stream; tie
+	// the relay's context to this call so any backing socat process is torn
+	// down as soon as PortForward returns, however it returns.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return portForward(ctx, r.client, podSandboxID, protocol, port, stream)
 }
 
 // ExecSync executes a command in the container, and returns the stdout output.
@@ -215,67 +238,159 @@ func protForwardTcp(containerPid int, port int32, stream io.ReadWriteCloser) err
 	return nil
 }
 
-func portForwardUdp(containerPid int, port int32, stream io.ReadWriteCloser) error {
-	unixDomainSocketPath := "/tmp/my.sock"
-	unixDomainSocketPath1 := "/tmp/1.sock"
+// waitForUnixSocket polls for sockPath to appear, returning once it does or
+// when ctx is cancelled / the timeout elapses.
+func waitForUnixSocket(ctx context.Context, sockPath string) error {
+	ctx, cancel := context.WithTimeout(ctx, udpPortForwardSocketReadyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(udpPortForwardSocketPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := os.Stat(sockPath); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// portForwardUdp relays UDP datagrams between the SPDY stream and the
+// container's network namespace. Since nsenter+socat can't bind directly
+// into a goroutine's file descriptors, we nsenter a socat process that
+// bridges a unix datagram socket (created in a private temp directory) to
+// the container-local UDP port, then relay the SPDY stream to and from that
+// unix socket from the host side.
+func portForwardUdp(ctx context.Context, containerPid int, port int32, stream io.ReadWriteCloser) error {
 	socatPath, lookupErr := exec.LookPath("socat")
 	if lookupErr != nil {
-		return fmt.Errorf("unable to do port forwarding: socat not found.")
+		return fmt.Errorf("unable to do port forwarding: socat not found")
 	}
-
-	args := []string{"-t", fmt.Sprintf("%d", containerPid), "-n", socatPath,
-	fmt.Sprintf("UNIX-RECVFROM:%s,fork", unixDomainSocketPath), fmt.Sprintf("UDP:localhost:%d", port)}
-
 	nsenterPath, lookupErr := exec.LookPath("nsenter")
 	if lookupErr != nil {
-		return fmt.Errorf("unable to do port forwarding: nsenter not found.")
+		return fmt.Errorf("unable to do port forwarding: nsenter not found")
 	}
 
+	tmpDir, err := ioutil.TempDir("", "kubelet-udp-portforward")
+	if err != nil {
+		return fmt.Errorf("unable to create temp dir for udp port forwarding: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Unique per-session socket paths so concurrent port-forward sessions to
+	// the same or different containers never collide.
+	relaySocketPath := filepath.Join(tmpDir, "relay.sock")
+	clientSocketPath := filepath.Join(tmpDir, "client.sock")
+
+	args := []string{"-t", fmt.Sprintf("%d", containerPid), "-n", socatPath,
+		fmt.Sprintf("UNIX-RECVFROM:%s,fork", relaySocketPath), fmt.Sprintf("UDP:localhost:%d", port)}
+
 	commandString := fmt.Sprintf("%s %s", nsenterPath, strings.Join(args, " "))
-	glog.V(3).Infof("haha: executing port forwarding command: %s", commandString)
+	glog.V(4).Infof("executing udp port forwarding command: %s", commandString)
 
 	command := exec.Command(nsenterPath, args...)
-	command.Stdout = stream
-
 	stderr := new(bytes.Buffer)
 	command.Stderr = stderr
-	glog.V(3).Infof("haha:  args are %s\n", args)
 
-	go func() error {
-		err := command.Run();
-		if err != nil {
-			glog.V(3).Infof("haha: execute went error \n")
-			return fmt.Errorf("%v: %s", err, stderr.String())
+	if err := command.Start(); err != nil {
+		return fmt.Errorf("unable to start socat: %v", err)
+	}
+
+	cmdDone := make(chan error, 1)
+	go func() {
+		cmdDone <- command.Wait()
+	}()
+
+	// Make sure the nsenter'd socat process is always cleaned up, whether we
+	// return because of an error, the stream closing, or ctx cancellation.
+	defer func() {
+		if command.ProcessState == nil {
+			command.Process.Kill()
+			<-cmdDone
+		}
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if command.ProcessState == nil {
+				command.Process.Kill()
+			}
+		case err := <-cmdDone:
+			// Hand the result back for the deferred cleanup and the relay
+			// loop, which also wait on cmdDone.
+			cmdDone <- err
 		}
-		return nil
 	}()
 
-	time.Sleep(3*time.Second)
+	if err := waitForUnixSocket(ctx, relaySocketPath); err != nil {
+		return fmt.Errorf("timed out waiting for socat relay socket %s: %v", relaySocketPath, err)
+	}
 
-	a, err := net.ResolveUnixAddr("unixgram", unixDomainSocketPath)
+	raddr, err := net.ResolveUnixAddr("unixgram", relaySocketPath)
 	if err != nil {
 		return err
 	}
-
-	b, err := net.ResolveUnixAddr("unixgram", unixDomainSocketPath1)
+	laddr, err := net.ResolveUnixAddr("unixgram", clientSocketPath)
 	if err != nil {
 		return err
 	}
 
-	//read stream from spdy, send buf to socat, then receive from socat send back to spdy
-	connUDP, err := net.DialUnix("unixgram", b, a)
+	connUDP, err := net.DialUnix("unixgram", laddr, raddr)
 	if err != nil {
-		glog.V(3).Infof("haha: dial went error %s\n", err)
-		return err
+		return fmt.Errorf("unable to dial udp relay socket: %v", err)
 	}
-	glog.V(3).Infof("haha:  dial unixgram success\n")
-	portforward.ReadFromStreamAndSendToUDP(stream, connUDP)
+	defer connUDP.Close()
 
+	// Relay in both directions: SPDY stream -> socat (-> container UDP port),
+	// and container UDP responses (via socat) -> SPDY stream.
+	relayErrs := make(chan error, 2)
+	go func() {
+		_, err := portforward.ReadFromStreamAndSendToUDP(stream, connUDP)
+		relayErrs <- err
+	}()
+	go func() {
+		relayErrs <- readFromUDPAndSendToStream(connUDP, stream)
+	}()
 
-	return nil
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-relayErrs:
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("error relaying udp port-forward traffic: %v", err)
+		}
+		return nil
+	case err := <-cmdDone:
+		if err != nil {
+			return fmt.Errorf("socat exited unexpectedly: %v: %s", err, stderr.String())
+		}
+		return fmt.Errorf("socat exited unexpectedly: %s", stderr.String())
+	}
+}
+
+// readFromUDPAndSendToStream copies datagrams read from conn onto stream
+// until conn is closed or a read/write error occurs.
+func readFromUDPAndSendToStream(conn *net.UnixConn, stream io.Writer) error {
+	buf := make([]byte, udpPortForwardBufferSize)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if _, werr := stream.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
 }
 
-func portForward(client libdocker.Interface, podSandboxID string, protocol string, port int32, stream io.ReadWriteCloser) error {
+func portForward(ctx context.Context, client libdocker.Interface, podSandboxID string, protocol string, port int32, stream io.ReadWriteCloser) error {
 	container, err := client.InspectContainer(podSandboxID)
 	if err != nil {
 		return err
@@ -287,10 +402,9 @@ func portForward(client libdocker.Interface, podSandboxID string, protocol strin
 
 	containerPid := container.State.Pid
 
-	glog.V(3).Infof("haha: portForward | the protocol is %s", protocol)
 	if protocol == api.PortForwardProtocolTypeUdp4 ||
 		protocol == api.PortForwardProtocolTypeUdp6 {
-		return portForwardUdp(containerPid, port, stream)
+		return portForwardUdp(ctx, containerPid, port, stream)
 	}
 
 	return protForwardTcp(containerPid, port, stream)
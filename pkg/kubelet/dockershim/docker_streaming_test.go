@@ -0,0 +1,180 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockershim
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaitForUnixSocket(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "udp-portforward-test")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sockPath := filepath.Join(tmpDir, "relay.sock")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		addr, err := net.ResolveUnixAddr("unixgram", sockPath)
+		if err != nil {
+			return
+		}
+		conn, err := net.ListenUnixgram("unixgram", addr)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+	}()
+
+	if err := waitForUnixSocket(context.Background(), sockPath); err != nil {
+		t.Fatalf("expected socket to appear, got error: %v", err)
+	}
+}
+
+func TestWaitForUnixSocketTimeout(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "udp-portforward-test")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := waitForUnixSocket(ctx, filepath.Join(tmpDir, "never-created.sock")); err == nil {
+		t.Fatal("expected an error waiting for a socket that is never created")
+	}
+}
+
+func TestReadFromUDPAndSendToStream(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "udp-portforward-test")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	serverAddr, err := net.ResolveUnixAddr("unixgram", filepath.Join(tmpDir, "server.sock"))
+	if err != nil {
+		t.Fatalf("unable to resolve server addr: %v", err)
+	}
+	server, err := net.ListenUnixgram("unixgram", serverAddr)
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer server.Close()
+
+	clientAddr, err := net.ResolveUnixAddr("unixgram", filepath.Join(tmpDir, "client.sock"))
+	if err != nil {
+		t.Fatalf("unable to resolve client addr: %v", err)
+	}
+	client, err := net.DialUnix("unixgram", clientAddr, serverAddr)
+	if err != nil {
+		t.Fatalf("unable to dial: %v", err)
+	}
+	defer client.Close()
+
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- readFromUDPAndSendToStream(server, &out)
+	}()
+
+	if _, err := client.Write([]byte("hello")); err != nil {
+		t.Fatalf("unable to write: %v", err)
+	}
+
+	// Closing the server's read side unblocks readFromUDPAndSendToStream.
+	time.Sleep(20 * time.Millisecond)
+	server.Close()
+	<-done
+
+	if out.String() != "hello" {
+		t.Fatalf("expected stream to receive %q, got %q", "hello", out.String())
+	}
+}
+
+// TestPortForwardUdpWithFakeSocat exercises the full nsenter/socat relay
+// path using fake binaries on PATH, skipping if a real environment can't
+// support running them (e.g. missing /bin/sh).
+func TestPortForwardUdpWithFakeSocat(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no shell available to fake nsenter/socat")
+	}
+
+	binDir, err := ioutil.TempDir("", "fake-bin")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(binDir)
+
+	// A fake nsenter that just execs its own arguments, dropping the nsenter
+	// flags, so "socat ..." runs unprivileged in the test process tree.
+	nsenterScript := "#!" + sh + "\nshift 3\nexec \"$@\"\n"
+	if err := ioutil.WriteFile(filepath.Join(binDir, "nsenter"), []byte(nsenterScript), 0755); err != nil {
+		t.Fatalf("unable to write fake nsenter: %v", err)
+	}
+
+	// A fake socat that just creates the unix socket it's told to listen on
+	// and otherwise idles, so portForwardUdp finds the socket and dials it;
+	// the test then checks only that portForwardUdp returns nil or the
+	// context deadline.
+	socatScript := "#!" + sh + `
+sock=$(echo "$1" | sed -n "s/^UNIX-RECVFROM:\\([^,]*\\),.*/\\1/p")
+python3 - "$sock" <<"EOF"
+import socket, sys, time
+s = socket.socket(socket.AF_UNIX, socket.SOCK_DGRAM)
+s.bind(sys.argv[1])
+time.sleep(5)
+EOF
+`
+	if err := ioutil.WriteFile(filepath.Join(binDir, "socat"), []byte(socatScript), 0755); err != nil {
+		t.Fatalf("unable to write fake socat: %v", err)
+	}
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available to back the fake socat binary")
+	}
+
+	oldPath := os.Getenv("PATH")
+	defer os.Setenv("PATH", oldPath)
+	os.Setenv("PATH", binDir+string(os.PathListSeparator)+oldPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	stream := &loopbackReadWriteCloser{}
+	err = portForwardUdp(ctx, os.Getpid(), 12345, stream)
+	if err != nil && err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error from portForwardUdp: %v", err)
+	}
+}
+
+type loopbackReadWriteCloser struct {
+	bytes.Buffer
+}
+
+func (l *loopbackReadWriteCloser) Close() error { return nil }
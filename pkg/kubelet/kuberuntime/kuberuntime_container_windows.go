@@ -20,16 +20,39 @@ package kuberuntime
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/docker/docker/pkg/sysinfo"
 
 	"k8s.io/api/core/v1"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
 	kubefeatures "k8s.io/kubernetes/pkg/features"
 	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
 	"k8s.io/kubernetes/pkg/securitycontext"
 )
 
+const (
+	// windowsCPUGroupAffinityAnnotation lets a pod pin a container's CPU
+	// count to a specific set of host logical processors, expressed as a
+	// comma-separated list of CPU indices (e.g. "0,1,2,3"). Windows CRI
+	// shims (containerd/hcsshim) honor CpuCount as the number of logical
+	// processors to grant the container, so pinning is approximated here by
+	// clamping CpuCount to the number of distinct valid indices listed
+	// rather than a limit-derived value; true
+	// per-core affinity masks aren't yet expressible through
+	// runtimeapi.WindowsContainerResources.
+	windowsCPUGroupAffinityAnnotation = "experimental.windows.kubernetes.io/cpu-group-affinity"
+
+	// windowsBestEffortCPUShares is the CPU shares value given to BestEffort
+	// pods, mirroring the minimum-shares floor the Linux cgroups path uses
+	// so BestEffort containers don't get starved of all CPU time on a busy
+	// node, but also don't compete with Burstable/Guaranteed workloads.
+	windowsBestEffortCPUShares = 2
+)
+
 // applyPlatformSpecificContainerConfig applies platform specific configurations to runtimeapi.ContainerConfig.
 func (m *kubeGenericRuntimeManager) applyPlatformSpecificContainerConfig(config *runtimeapi.ContainerConfig, container *v1.Container, pod *v1.Pod, uid *int64, username string) error {
 	windowsConfig, err := m.generateWindowsContainerConfig(container, pod, uid, username)
@@ -81,8 +104,24 @@ func (m *kubeGenericRuntimeManager) generateWindowsContainerConfig(container *v1
 	if cpuShares == 0 {
 		cpuShares = milliCPUToShares(cpuRequest.MilliValue(), isolatedByHyperv)
 	}
+
+	// Honor the Pod's QoS class the same way the Linux cgroups path does:
+	// BestEffort containers (no requests or limits set at all) get a fixed,
+	// low share count instead of whatever milliCPUToShares(0, ...) happens
+	// to floor to, so they're consistently deprioritized under contention.
+	if v1qos.GetPodQOS(pod) == v1.PodQOSBestEffort {
+		cpuShares = windowsBestEffortCPUShares
+	}
 	wc.Resources.CpuShares = cpuShares
 
+	if affinityIDs, ok := pod.Annotations[windowsCPUGroupAffinityAnnotation]; ok {
+		cpuCount, err := parseCPUGroupAffinity(affinityIDs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %v", windowsCPUGroupAffinityAnnotation, err)
+		}
+		wc.Resources.CpuCount = cpuCount
+	}
+
 	memoryLimit := container.Resources.Limits.Memory().Value()
 	if memoryLimit != 0 {
 		wc.Resources.MemoryLimitInBytes = memoryLimit
@@ -110,3 +149,29 @@ func (m *kubeGenericRuntimeManager) generateWindowsContainerConfig(container *v1
 
 	return wc, nil
 }
+
+// parseCPUGroupAffinity parses the comma-separated logical processor index
+// list from the windowsCPUGroupAffinityAnnotation annotation and returns how
+// many CPUs it pins the container to.
+func parseCPUGroupAffinity(value string) (int64, error) {
+	ids := strings.Split(value, ",")
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		cpuID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid CPU index %q: %v", id, err)
+		}
+		if cpuID < 0 {
+			return 0, fmt.Errorf("CPU index %q must not be negative", id)
+		}
+		seen[cpuID] = struct{}{}
+	}
+	if len(seen) == 0 {
+		return 0, fmt.Errorf("no CPU indices specified")
+	}
+	return int64(len(seen)), nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// Pod DNS policies, mirrored here from k8s.io/api/core/v1 so this package
+// doesn't need to import the full core API just to compare policy strings.
+const (
+	DNSClusterFirstWithHostNet = "ClusterFirstWithHostNet"
+	DNSClusterFirst            = "ClusterFirst"
+	DNSDefault                 = "Default"
+	DNSNone                    = "None"
+)
+
+// netRegistry is the registry path holding the host's global DNS SearchList,
+// read by WindowsDNSConfigurer.hostSearchList below. It lives in this
+// tag-free file (rather than dns_windows.go) because hostSearchList, and the
+// tests that exercise it through fakeRegistryReader, build on every
+// platform.
+const netRegistry = `System\CurrentControlSet\Services\TCPIP\Parameters`
+
+// registryReader abstracts the Windows registry reads that back
+// WindowsDNSConfigurer, so the search-list merging logic below can be unit
+// tested on any platform with a fake implementation instead of requiring the
+// real windows/registry package.
+type registryReader interface {
+	// GetValue returns the named value under the given registry path, or ""
+	// if it isn't set.
+	GetValue(path, name string) string
+	// InterfaceSearchLists returns the per-interface Domain and SearchList
+	// values, keyed by interface name, for every network interface found
+	// under the TCPIP interfaces registry key.
+	InterfaceSearchLists() (map[string]windowsInterfaceDNSInfo, error)
+}
+
+// windowsInterfaceDNSInfo holds the per-interface "Connection-Specific DNS
+// Suffix" (Domain) and SearchList values read from the registry.
+type windowsInterfaceDNSInfo struct {
+	Domain     string
+	SearchList []string
+}
+
+// WindowsDNSConfigurer computes the effective DNS servers and search suffix
+// list for a Pod running on a Windows node, honoring Pod dnsPolicy and
+// dnsConfig.Searches the same way the Linux resolver does.
+type WindowsDNSConfigurer struct {
+	reader registryReader
+}
+
+// NewWindowsDNSConfigurer returns a WindowsDNSConfigurer backed by reader.
+func NewWindowsDNSConfigurer(reader registryReader) *WindowsDNSConfigurer {
+	return &WindowsDNSConfigurer{reader: reader}
+}
+
+// hostSearchList returns the deduplicated, priority-ordered list of DNS
+// search suffixes configured on the host: the global SearchList first, then
+// each interface's Connection-Specific DNS Suffix (Domain) followed by its
+// own SearchList. Interfaces are read from a map, so the order between
+// different interfaces' suffixes is not defined.
+func (c *WindowsDNSConfigurer) hostSearchList() ([]string, error) {
+	var all []string
+	appendUnique(&all, splitSuffixList(c.reader.GetValue(netRegistry, "SearchList")))
+
+	ifaces, err := c.reader.InterfaceSearchLists()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range ifaces {
+		if info.Domain != "" {
+			appendUnique(&all, []string{info.Domain})
+		}
+		appendUnique(&all, info.SearchList)
+	}
+	return all, nil
+}
+
+// GetPodDNS returns the effective DNSConfig for a Pod with the given
+// clusterDomain, namespace, dnsPolicy, and dnsConfig.Searches, merging in
+// the host's own DNS servers/search suffixes as appropriate for dnsPolicy.
+func (c *WindowsDNSConfigurer) GetPodDNS(hostDNS []string, clusterDomain, namespace, dnsPolicy string, dnsConfigSearches []string) (*runtimeapi.DNSConfig, error) {
+	switch dnsPolicy {
+	case DNSNone:
+		// Nothing from the host; only what the Pod's own dnsConfig provides.
+		return &runtimeapi.DNSConfig{Searches: dedupe(dnsConfigSearches)}, nil
+
+	case DNSDefault:
+		// Use the node's own resolver configuration, plus whatever the Pod's
+		// dnsConfig appends.
+		hostSearch, err := c.hostSearchList()
+		if err != nil {
+			return nil, err
+		}
+		searches := hostSearch
+		appendUnique(&searches, dnsConfigSearches)
+		return &runtimeapi.DNSConfig{Servers: hostDNS, Searches: searches}, nil
+
+	case DNSClusterFirst, DNSClusterFirstWithHostNet:
+		hostSearch, err := c.hostSearchList()
+		if err != nil {
+			return nil, err
+		}
+		searches := clusterSearchList(clusterDomain, namespace)
+		appendUnique(&searches, hostSearch)
+		appendUnique(&searches, dnsConfigSearches)
+		return &runtimeapi.DNSConfig{Servers: hostDNS, Searches: searches}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported dnsPolicy %q", dnsPolicy)
+	}
+}
+
+// clusterSearchList returns the cluster-scoped search suffixes a Pod gets
+// under ClusterFirst, in the same <namespace>.svc.<domain>, svc.<domain>,
+// <domain> order used on Linux.
+func clusterSearchList(clusterDomain, namespace string) []string {
+	if clusterDomain == "" {
+		return nil
+	}
+	var searches []string
+	if namespace != "" {
+		searches = append(searches, fmt.Sprintf("%s.svc.%s", namespace, clusterDomain))
+	}
+	searches = append(searches, fmt.Sprintf("svc.%s", clusterDomain), clusterDomain)
+	return searches
+}
+
+// splitSuffixList splits a comma-separated registry SearchList value,
+// dropping empty entries.
+func splitSuffixList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var out []string
+	start := 0
+	for i := 0; i <= len(value); i++ {
+		if i == len(value) || value[i] == ',' {
+			if s := value[start:i]; s != "" {
+				out = append(out, s)
+			}
+			start = i + 1
+		}
+	}
+	return out
+}
+
+// appendUnique appends each element of add to *base that isn't already
+// present, preserving the priority order of both slices.
+func appendUnique(base *[]string, add []string) {
+	for _, elem := range add {
+		if !elemInList(elem, *base) {
+			*base = append(*base, elem)
+		}
+	}
+}
+
+func elemInList(elem string, list []string) bool {
+	for _, e := range list {
+		if e == elem {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupe returns a copy of in with duplicates removed, preserving order.
+func dedupe(in []string) []string {
+	var out []string
+	appendUnique(&out, in)
+	return out
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fakeRegistryReader lets us exercise WindowsDNSConfigurer's merge logic
+// without touching the real Windows registry, so this test runs on any OS.
+type fakeRegistryReader struct {
+	values     map[string]string
+	interfaces map[string]windowsInterfaceDNSInfo
+}
+
+func (f *fakeRegistryReader) GetValue(path, name string) string {
+	return f.values[path+"\\"+name]
+}
+
+func (f *fakeRegistryReader) InterfaceSearchLists() (map[string]windowsInterfaceDNSInfo, error) {
+	return f.interfaces, nil
+}
+
+func TestGetPodDNSClusterFirst(t *testing.T) {
+	reader := &fakeRegistryReader{
+		values: map[string]string{
+			netRegistry + "\\SearchList": "corp.example.com",
+		},
+		interfaces: map[string]windowsInterfaceDNSInfo{
+			"Ethernet": {Domain: "eth.example.com"},
+		},
+	}
+	configurer := NewWindowsDNSConfigurer(reader)
+
+	cfg, err := configurer.GetPodDNS([]string{"10.0.0.10"}, "cluster.local", "default", DNSClusterFirst, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"default.svc.cluster.local",
+		"svc.cluster.local",
+		"cluster.local",
+		"corp.example.com",
+		"eth.example.com",
+	}
+	if !reflect.DeepEqual(cfg.Searches, want) {
+		t.Fatalf("searches = %v, want %v", cfg.Searches, want)
+	}
+	if !reflect.DeepEqual(cfg.Servers, []string{"10.0.0.10"}) {
+		t.Fatalf("servers = %v, want host DNS servers", cfg.Servers)
+	}
+}
+
+func TestGetPodDNSWithDNSConfigSearches(t *testing.T) {
+	reader := &fakeRegistryReader{values: map[string]string{}}
+	configurer := NewWindowsDNSConfigurer(reader)
+
+	cfg, err := configurer.GetPodDNS(nil, "cluster.local", "kube-system", DNSClusterFirst, []string{"extra.example.com", "cluster.local"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"kube-system.svc.cluster.local",
+		"svc.cluster.local",
+		"cluster.local",
+		"extra.example.com",
+	}
+	if !reflect.DeepEqual(cfg.Searches, want) {
+		t.Fatalf("searches = %v, want %v (dnsConfig.Searches should be appended and deduped)", cfg.Searches, want)
+	}
+}
+
+func TestGetPodDNSNone(t *testing.T) {
+	reader := &fakeRegistryReader{values: map[string]string{}}
+	configurer := NewWindowsDNSConfigurer(reader)
+
+	cfg, err := configurer.GetPodDNS([]string{"10.0.0.10"}, "cluster.local", "default", DNSNone, []string{"only.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Servers) != 0 {
+		t.Fatalf("dnsPolicy None should not inherit host DNS servers, got %v", cfg.Servers)
+	}
+	if !reflect.DeepEqual(cfg.Searches, []string{"only.example.com"}) {
+		t.Fatalf("searches = %v, want only the Pod's own dnsConfig.Searches", cfg.Searches)
+	}
+}
+
+func TestGetPodDNSInvalidPolicy(t *testing.T) {
+	configurer := NewWindowsDNSConfigurer(&fakeRegistryReader{values: map[string]string{}})
+	if _, err := configurer.GetPodDNS(nil, "cluster.local", "default", "NotAPolicy", nil); err == nil {
+		t.Fatal("expected an error for an unsupported dnsPolicy")
+	}
+}
+
+func TestSplitSuffixList(t *testing.T) {
+	cases := map[string][]string{
+		"":                nil,
+		"a.com":           {"a.com"},
+		"a.com,b.com":     {"a.com", "b.com"},
+		"a.com,,b.com,":   {"a.com", "b.com"},
+	}
+	for input, want := range cases {
+		got := splitSuffixList(input)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("splitSuffixList(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
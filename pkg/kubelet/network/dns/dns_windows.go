@@ -32,8 +32,9 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// netRegistry is defined in dns_configurer.go, which is tag-free so that
+// WindowsDNSConfigurer and its tests build on every platform.
 const (
-	netRegistry       = `System\CurrentControlSet\Services\TCPIP\Parameters`
 	netIfacesRegistry = `System\CurrentControlSet\Services\TCPIP\Parameters\Interfaces`
 	maxHostnameLen    = 128
 	maxDomainNameLen  = 128
@@ -60,6 +61,10 @@ var (
 	procGetNetworkParams = iphlpapidll.MustFindProc("GetNetworkParams")
 )
 
+// getHostDNSConfig returns the node's own DNS servers/search suffixes. Pod-
+// specific merging (dnsPolicy, dnsConfig.Searches, ClusterDomain/Namespace)
+// is handled by WindowsDNSConfigurer.GetPodDNS in dns_configurer.go, which
+// this function's caller should use instead for anything Pod-scoped.
 func getHostDNSConfig(resolverConfig string) (*runtimeapi.DNSConfig, error) {
 	var (
 		hostDNS, hostSearch []string
@@ -72,7 +77,7 @@ func getHostDNSConfig(resolverConfig string) (*runtimeapi.DNSConfig, error) {
 			klog.ErrorS(err, "Could not get the host's DNS Server List")
 			return nil, err
 		}
-		hostSearch, err = getDNSSuffixList()
+		hostSearch, err = newRegistryReader().InterfaceSearchListsFlattened()
 		if err != nil {
 			klog.ErrorS(err, "Could not get the host's DNS Suffix List")
 			return nil, err
@@ -84,39 +89,31 @@ func getHostDNSConfig(resolverConfig string) (*runtimeapi.DNSConfig, error) {
 	}, nil
 }
 
-func elemInList(elem string, list []string) bool {
-	for _, e := range list {
-		if e == elem {
-			return true
-		}
+// getPodDNSConfig returns the effective DNS config for a Pod scheduled onto
+// this Windows node, honoring dnsPolicy and dnsConfig.Searches the same way
+// the Linux resolver does.
+func getPodDNSConfig(clusterDomain, namespace, dnsPolicy string, dnsConfigSearches []string) (*runtimeapi.DNSConfig, error) {
+	hostDNS, err := getDNSServerList()
+	if err != nil {
+		klog.ErrorS(err, "Could not get the host's DNS Server List")
+		return nil, err
 	}
-	return false
+	return NewWindowsDNSConfigurer(newRegistryReader()).GetPodDNS(hostDNS, clusterDomain, namespace, dnsPolicy, dnsConfigSearches)
 }
 
-func getRegistryValue(reg, key string) string {
-	regKey, err := registry.OpenKey(registry.LOCAL_MACHINE, reg, registry.QUERY_VALUE)
-	if err != nil {
-		return ""
-	}
-	defer regKey.Close()
+// winRegistryReader is the production registryReader backed by the real
+// windows/registry package.
+type winRegistryReader struct{}
 
-	regValue, _, err := regKey.GetStringValue(key)
-	if err != nil {
-		return ""
-	}
-	return regValue
+func newRegistryReader() *winRegistryReader {
+	return &winRegistryReader{}
 }
 
-// getDNSSuffixList reads DNS config file and returns the list of configured DNS suffixes
-func getDNSSuffixList() ([]string, error) {
-	// We start with the general suffix list that apply to all network connections.
-	allSuffixes := []string{}
-	suffixes := getRegistryValue(netRegistry, "SearchList")
-	if suffixes != "" {
-		allSuffixes = strings.Split(suffixes, ",")
-	}
+func (winRegistryReader) GetValue(path, name string) string {
+	return getRegistryValue(path, name)
+}
 
-	// Then we append the network-specific DNS suffix lists.
+func (r winRegistryReader) InterfaceSearchLists() (map[string]windowsInterfaceDNSInfo, error) {
 	regKey, err := registry.OpenKey(registry.LOCAL_MACHINE, netIfacesRegistry, registry.ENUMERATE_SUB_KEYS)
 	if err != nil {
 		return nil, err
@@ -127,19 +124,36 @@ func getDNSSuffixList() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	result := make(map[string]windowsInterfaceDNSInfo, len(ifaces))
 	for _, iface := range ifaces {
-		suffixes := getRegistryValue(fmt.Sprintf("%s\\%s", netIfacesRegistry, iface), "SearchList")
-		if suffixes == "" {
-			continue
-		}
-		for _, suffix := range strings.Split(suffixes, ",") {
-			if !elemInList(suffix, allSuffixes) {
-				allSuffixes = append(allSuffixes, suffix)
-			}
+		ifacePath := fmt.Sprintf("%s\\%s", netIfacesRegistry, iface)
+		result[iface] = windowsInterfaceDNSInfo{
+			Domain:     r.GetValue(ifacePath, "Domain"),
+			SearchList: splitSuffixList(r.GetValue(ifacePath, "SearchList")),
 		}
 	}
+	return result, nil
+}
+
+// InterfaceSearchListsFlattened is a convenience used by getHostDNSConfig,
+// which predates per-Pod DNS handling and just wants a single flat list.
+func (r winRegistryReader) InterfaceSearchListsFlattened() ([]string, error) {
+	return NewWindowsDNSConfigurer(r).hostSearchList()
+}
+
+func getRegistryValue(reg, key string) string {
+	regKey, err := registry.OpenKey(registry.LOCAL_MACHINE, reg, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer regKey.Close()
 
-	return allSuffixes, nil
+	regValue, _, err := regKey.GetStringValue(key)
+	if err != nil {
+		return ""
+	}
+	return regValue
 }
 
 func getNetworkParams() (*FixedInfo, error) {
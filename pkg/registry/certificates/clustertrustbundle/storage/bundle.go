@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/kubernetes/pkg/apis/certificates"
+)
+
+// BundleREST implements the /bundle subresource of ClusterTrustBundle. It
+// computes, server-side, the concatenated, de-duplicated, PEM-normalized
+// trust anchors for every ClusterTrustBundle that matches a signer name or
+// label selector, so consumers like the kubelet's projected-volume source
+// and CSI drivers don't each have to reimplement aggregation.
+type BundleREST struct {
+	store *genericregistry.Store
+}
+
+var _ rest.Storage = &BundleREST{}
+var _ rest.Connecter = &BundleREST{}
+
+// New returns an empty ClusterTrustBundle, satisfying rest.Storage. The
+// subresource itself doesn't round-trip a typed object; the real response
+// body is a raw PEM stream written by the http.Handler returned from
+// Connect.
+func (r *BundleREST) New() runtime.Object {
+	return &certificates.ClusterTrustBundle{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *BundleREST) Destroy() {}
+
+// ConnectMethods returns the list of HTTP methods handled by Connect.
+func (r *BundleREST) ConnectMethods() []string {
+	return []string{"GET"}
+}
+
+// NewConnectOptions returns an empty options object so the generic apiserver
+// machinery binds ?signerName= and ?labelSelector= query parameters onto it
+// before calling Connect.
+func (r *BundleREST) NewConnectOptions() (runtime.Object, bool, string) {
+	return &BundleAggregationOptions{}, false, ""
+}
+
+// Connect returns an http.Handler that writes the aggregated, de-duplicated
+// PEM trust bundle for the ClusterTrustBundles selected by opts, honoring
+// If-None-Match against the response's ETag for conditional GETs.
+func (r *BundleREST) Connect(ctx context.Context, name string, options runtime.Object, responder rest.Responder) (http.Handler, error) {
+	opts, ok := options.(*BundleAggregationOptions)
+	if !ok {
+		return nil, fmt.Errorf("invalid options object: %#v", options)
+	}
+	if (opts.SignerName == "") == (opts.LabelSelector == "") {
+		return nil, fmt.Errorf("exactly one of signerName or labelSelector must be specified")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		bundlePEM, etag, err := r.aggregate(ctx, opts)
+		if err != nil {
+			responder.Error(err)
+			return
+		}
+
+		if inm := req.Header.Get("If-None-Match"); inm == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/x-pem-file")
+		w.Write(bundlePEM)
+	}), nil
+}
+
+// aggregate walks every ClusterTrustBundle matching opts, parses its
+// spec.trustBundle, and returns a single deterministic PEM stream with
+// expired and duplicate (by subject key identifier) certificates removed.
+// The returned ETag is derived from the sorted set of certificate SHA-256
+// fingerprints, so it only changes when the effective trust anchor set
+// changes.
+func (r *BundleREST) aggregate(ctx context.Context, opts *BundleAggregationOptions) ([]byte, string, error) {
+	var selector labels.Selector
+	if opts.LabelSelector != "" {
+		var err error
+		selector, err = labels.Parse(opts.LabelSelector)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid labelSelector: %v", err)
+		}
+	} else {
+		selector = labels.Everything()
+	}
+
+	listObj, err := r.store.List(ctx, &metainternalversion.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, "", err
+	}
+	list, ok := listObj.(*certificates.ClusterTrustBundleList)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected list type %T", listObj)
+	}
+
+	now := time.Now()
+	seenBySKI := map[string]*x509.Certificate{}
+	for i := range list.Items {
+		ctb := &list.Items[i]
+		if opts.SignerName != "" && ctb.Spec.SignerName != opts.SignerName {
+			continue
+		}
+
+		remaining := []byte(ctb.Spec.TrustBundle)
+		for {
+			var block *pem.Block
+			block, remaining = pem.Decode(remaining)
+			if block == nil {
+				break
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if now.After(cert.NotAfter) {
+				continue
+			}
+			ski := string(cert.SubjectKeyId)
+			if ski == "" {
+				// No SKI to de-duplicate on; fall back to the raw DER bytes
+				// so we still avoid exact duplicates.
+				ski = string(cert.Raw)
+			}
+			if _, ok := seenBySKI[ski]; !ok {
+				seenBySKI[ski] = cert
+			}
+		}
+	}
+
+	fingerprints := make([]string, 0, len(seenBySKI))
+	certsByFingerprint := make(map[string]*x509.Certificate, len(seenBySKI))
+	for _, cert := range seenBySKI {
+		sum := sha256.Sum256(cert.Raw)
+		fp := hex.EncodeToString(sum[:])
+		fingerprints = append(fingerprints, fp)
+		certsByFingerprint[fp] = cert
+	}
+	sort.Strings(fingerprints)
+
+	var out []byte
+	for _, fp := range fingerprints {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certsByFingerprint[fp].Raw})...)
+	}
+
+	etagSum := sha256.Sum256([]byte(fmt.Sprintf("%v", fingerprints)))
+	etag := `"` + hex.EncodeToString(etagSum[:]) + `"`
+
+	return out, etag, nil
+}
+
+// BundleAggregationOptions is the query-parameter object bound from
+// ?signerName= or ?labelSelector= on a GET to the /bundle subresource.
+type BundleAggregationOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// SignerName restricts aggregation to ClusterTrustBundles with this
+	// spec.signerName. Mutually exclusive with LabelSelector.
+	SignerName string `json:"signerName,omitempty"`
+	// LabelSelector restricts aggregation to ClusterTrustBundles matching
+	// this label selector. Mutually exclusive with SignerName.
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (o *BundleAggregationOptions) DeepCopyObject() runtime.Object {
+	if o == nil {
+		return nil
+	}
+	out := new(BundleAggregationOptions)
+	*out = *o
+	return out
+}
@@ -38,8 +38,10 @@ var _ rest.StandardStorage = &REST{}
 var _ rest.TableConvertor = &REST{}
 var _ genericregistry.GenericStore = &REST{}
 
-// NewREST returns a RESTStorage object for ClusterTrustBundle objects.
-func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, error) {
+// NewREST returns a RESTStorage object for ClusterTrustBundle objects, along
+// with the "bundle" subresource storage for server-side aggregation of
+// trust anchors.
+func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, *BundleREST, error) {
 	store := &genericregistry.Store{
 		NewFunc:                   func() runtime.Object { return &certificates.ClusterTrustBundle{} },
 		NewListFunc:               func() runtime.Object { return &certificates.ClusterTrustBundleList{} },
@@ -55,7 +57,7 @@ func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, error) {
 	}
 	options := &generic.StoreOptions{RESTOptions: optsGetter, AttrFunc: certificates.ClusterTrustBundleGetAttrs}
 	if err := store.CompleteWithOptions(options); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return &REST{store}, nil
+	return &REST{store}, &BundleREST{store: store}, nil
 }
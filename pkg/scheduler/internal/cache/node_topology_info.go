@@ -0,0 +1,179 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// TopologyPair is a node label (e.g. the zone in
+// topology.kubernetes.io/zone=us-east-1a) that a PodTopologySpreadConstraint
+// spreads pods evenly over.
+type TopologyPair struct {
+	Key   string
+	Value string
+}
+
+// NodeTopologyInfo indexes, for every label on every node in the cluster,
+// which nodes carry that label/value pair.
+type NodeTopologyInfo map[TopologyPair]sets.String
+
+// AddNode indexes node's labels into i.
+func (i NodeTopologyInfo) AddNode(node *v1.Node) {
+	for k, v := range node.Labels {
+		pair := TopologyPair{Key: k, Value: v}
+		nodes, ok := i[pair]
+		if !ok {
+			nodes = sets.NewString()
+			i[pair] = nodes
+		}
+		nodes.Insert(node.Name)
+	}
+}
+
+// RemoveNode removes node's labels from i, dropping any TopologyPair entry
+// that no longer has any node behind it.
+func (i NodeTopologyInfo) RemoveNode(node *v1.Node) {
+	for k, v := range node.Labels {
+		pair := TopologyPair{Key: k, Value: v}
+		nodes, ok := i[pair]
+		if !ok {
+			continue
+		}
+		nodes.Delete(node.Name)
+		if nodes.Len() == 0 {
+			delete(i, pair)
+		}
+	}
+}
+
+// UpdateNode reindexes a node whose labels changed from oldNode to newNode.
+func (i NodeTopologyInfo) UpdateNode(oldNode, newNode *v1.Node) {
+	i.RemoveNode(oldNode)
+	i.AddNode(newNode)
+}
+
+// podAssignment is a pod along with the node it's (tentatively, for an
+// assumed pod, or actually) running on, which is all PodTopologySpreadCache
+// needs to recompute per-topology-value pod counts for an arbitrary
+// LabelSelector.
+type podAssignment struct {
+	pod  *v1.Pod
+	node *v1.Node
+}
+
+// PodTopologySpreadCache tracks which pods are assigned to which nodes, so
+// that Match can answer "how many pods matching this constraint's
+// LabelSelector are on a node with this constraint's TopologyKey set to
+// each value" without listing every pod in the cluster on every scheduling
+// attempt. Unlike NodeTopologyInfo, which only depends on node labels, this
+// has to be recomputed per PodTopologySpreadConstraint, since each
+// constraint can select a different subset of pods.
+type PodTopologySpreadCache struct {
+	assignments map[string]podAssignment // keyed by pod namespace/name
+}
+
+// NewPodTopologySpreadCache returns an empty PodTopologySpreadCache.
+func NewPodTopologySpreadCache() *PodTopologySpreadCache {
+	return &PodTopologySpreadCache{assignments: make(map[string]podAssignment)}
+}
+
+// AddPod records pod as running on node.
+func (c *PodTopologySpreadCache) AddPod(pod *v1.Pod, node *v1.Node) {
+	c.assignments[podKey(pod)] = podAssignment{pod: pod, node: node}
+}
+
+// RemovePod undoes a prior AddPod for pod.
+func (c *PodTopologySpreadCache) RemovePod(pod *v1.Pod) {
+	delete(c.assignments, podKey(pod))
+}
+
+// Match returns, for every value topologyKey takes across the nodes
+// carrying an assigned pod, the number of assigned pods matching selector
+// running on a node with that value. Nodes that don't carry topologyKey at
+// all don't contribute an entry, since they aren't part of this topology
+// domain.
+func (c *PodTopologySpreadCache) Match(selector labels.Selector, topologyKey string) map[string]int {
+	counts := make(map[string]int)
+	for _, a := range c.assignments {
+		value, ok := a.node.Labels[topologyKey]
+		if !ok {
+			continue
+		}
+		if _, ok := counts[value]; !ok {
+			counts[value] = 0
+		}
+		if selector != nil && !selector.Matches(labels.Set(a.pod.Labels)) {
+			continue
+		}
+		counts[value]++
+	}
+	return counts
+}
+
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// FitsTopologySpreadConstraint reports whether scheduling a new pod with
+// topologyValue for constraint.TopologyKey would violate constraint.MaxSkew,
+// given counts (as returned by PodTopologySpreadCache.Match for that
+// constraint's selector and TopologyKey).
+//
+// Skew is max(count)-min(count) across every value the TopologyKey takes
+// among nodes that carry it. Only WhenUnsatisfiable=DoNotSchedule actually
+// rejects the node; ScheduleAnyway only affects the node's score, via
+// ScoreTopologySpread.
+func FitsTopologySpreadConstraint(counts map[string]int, topologyValue string, constraint v1.TopologySpreadConstraint) bool {
+	if constraint.WhenUnsatisfiable != v1.DoNotSchedule {
+		return true
+	}
+	min, max := projectedSkewBounds(counts, topologyValue)
+	return int32(max-min) <= constraint.MaxSkew
+}
+
+// ScoreTopologySpread scores a candidate node inversely to the skew that
+// placing a pod with topologyValue would cause: lower resulting skew scores
+// higher, so among several nodes that all satisfy MaxSkew the scheduler
+// still prefers spreading pods over the topology domain evenly.
+func ScoreTopologySpread(counts map[string]int, topologyValue string) int64 {
+	min, max := projectedSkewBounds(counts, topologyValue)
+	return int64(min) - int64(max)
+}
+
+func projectedSkewBounds(counts map[string]int, topologyValue string) (min, max int) {
+	projected := make(map[string]int, len(counts))
+	for value, count := range counts {
+		projected[value] = count
+	}
+	projected[topologyValue]++
+
+	first := true
+	for _, count := range projected {
+		if first {
+			min, max = count, count
+			first = false
+			continue
+		}
+		if count < min {
+			min = count
+		}
+		if count > max {
+			max = count
+		}
+	}
+	return min, max
+}
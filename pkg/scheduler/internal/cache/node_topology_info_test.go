@@ -19,6 +19,7 @@ import (
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -163,3 +164,113 @@ func TestUpdateNode(t *testing.T) {
 		})
 	}
 }
+
+func zoneNode(name, zone string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"zone": zone},
+		},
+	}
+}
+
+func labeledPod(name string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+	}
+}
+
+func TestPodTopologySpreadCacheMatch(t *testing.T) {
+	nodeA := zoneNode("nodeA", "zoneA")
+	nodeB := zoneNode("nodeB", "zoneB")
+
+	tests := []struct {
+		name     string
+		pods     []*v1.Pod
+		nodes    []*v1.Node
+		selector labels.Selector
+		expected map[string]int
+	}{
+		{
+			name:     "counts only pods matching the selector",
+			pods:     []*v1.Pod{labeledPod("p1", map[string]string{"app": "foo"}), labeledPod("p2", map[string]string{"app": "bar"})},
+			nodes:    []*v1.Node{nodeA, nodeB},
+			selector: labels.SelectorFromSet(labels.Set{"app": "foo"}),
+			expected: map[string]int{"zoneA": 1, "zoneB": 0},
+		},
+		{
+			name:     "nil selector matches every pod",
+			pods:     []*v1.Pod{labeledPod("p1", map[string]string{"app": "foo"}), labeledPod("p2", map[string]string{"app": "bar"})},
+			nodes:    []*v1.Node{nodeA, nodeB},
+			selector: nil,
+			expected: map[string]int{"zoneA": 1, "zoneB": 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewPodTopologySpreadCache()
+			for i, pod := range test.pods {
+				c.AddPod(pod, test.nodes[i])
+			}
+			if got := c.Match(test.selector, "zone"); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("Match() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestPodTopologySpreadCacheRemovePod(t *testing.T) {
+	nodeA := zoneNode("nodeA", "zoneA")
+	pod := labeledPod("p1", map[string]string{"app": "foo"})
+
+	c := NewPodTopologySpreadCache()
+	c.AddPod(pod, nodeA)
+	c.RemovePod(pod)
+
+	if got, want := c.Match(nil, "zone"), map[string]int{}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() after RemovePod = %+v, want %+v", got, want)
+	}
+}
+
+func TestFitsTopologySpreadConstraint(t *testing.T) {
+	tests := []struct {
+		name          string
+		counts        map[string]int
+		topologyValue string
+		constraint    v1.TopologySpreadConstraint
+		expected      bool
+	}{
+		{
+			name:          "within max skew",
+			counts:        map[string]int{"zoneA": 1, "zoneB": 1},
+			topologyValue: "zoneA",
+			constraint:    v1.TopologySpreadConstraint{MaxSkew: 1, WhenUnsatisfiable: v1.DoNotSchedule},
+			expected:      true,
+		},
+		{
+			name:          "exceeds max skew",
+			counts:        map[string]int{"zoneA": 2, "zoneB": 0},
+			topologyValue: "zoneA",
+			constraint:    v1.TopologySpreadConstraint{MaxSkew: 1, WhenUnsatisfiable: v1.DoNotSchedule},
+			expected:      false,
+		},
+		{
+			name:          "ScheduleAnyway never rejects",
+			counts:        map[string]int{"zoneA": 5, "zoneB": 0},
+			topologyValue: "zoneA",
+			constraint:    v1.TopologySpreadConstraint{MaxSkew: 1, WhenUnsatisfiable: v1.ScheduleAnyway},
+			expected:      true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := FitsTopologySpreadConstraint(test.counts, test.topologyValue, test.constraint); got != test.expected {
+				t.Errorf("FitsTopologySpreadConstraint() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
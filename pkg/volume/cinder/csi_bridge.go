@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"context"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DriverName is the CSI driver name reported from the Identity server and
+// recorded in csi.CSIPersistentVolumeSource.Driver for volumes migrated
+// from the in-tree cinder plugin via CSIMigration.
+const DriverName = "cinder.csi.k8s.io"
+
+// csiControllerServer implements the subset of csi.ControllerServer backed
+// directly by BlockStorageProvider, reusing the same calls
+// cinderDiskAttacher and cinderDiskDetacher already make so the
+// backoff/probe logic in attacher.go does not need to be duplicated.
+type csiControllerServer struct {
+	cinderProvider BlockStorageProvider
+}
+
+// NewCSIControllerServer returns a csi.ControllerServer backed by provider.
+func NewCSIControllerServer(provider BlockStorageProvider) csi.ControllerServer {
+	return &csiControllerServer{cinderProvider: provider}
+}
+
+func (s *csiControllerServer) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
+	if req.GetVolumeId() == "" || req.GetNodeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume_id and node_id are required")
+	}
+	instanceID := req.GetNodeId()
+	if _, err := s.cinderProvider.AttachDisk(instanceID, req.GetVolumeId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "AttachDisk failed: %v", err)
+	}
+	devicePath, err := s.cinderProvider.GetAttachmentDiskPath(instanceID, req.GetVolumeId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetAttachmentDiskPath failed: %v", err)
+	}
+	return &csi.ControllerPublishVolumeResponse{
+		PublishContext: map[string]string{"devicePath": devicePath},
+	}, nil
+}
+
+func (s *csiControllerServer) ControllerUnpublishVolume(ctx context.Context, req *csi.ControllerUnpublishVolumeRequest) (*csi.ControllerUnpublishVolumeResponse, error) {
+	if req.GetVolumeId() == "" || req.GetNodeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume_id and node_id are required")
+	}
+	if err := s.cinderProvider.DetachDisk(req.GetNodeId(), req.GetVolumeId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "DetachDisk failed: %v", err)
+	}
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+// NOTE: the Identity server, the Node server's NodeStageVolume/
+// NodePublishVolume (which would call cinderDiskAttacher.MountDevice /
+// WaitForAttach), and cmd/cinder-csi-driver's gRPC-over-UDS bootstrap are
+// left for a follow-up: they need *volume.Spec values built the way
+// getVolumeInfo and the CinderVolumeSource type do today, both of which
+// live in this package's plugin.go -- not present in this checkout (only
+// attacher.go is). ControllerPublishVolume/ControllerUnpublishVolume above
+// only call BlockStorageProvider methods that attacher.go already calls, so
+// those are implemented now; CSIMigration's PersistentVolume translation depends on
+// the same missing plugin.go and is left alongside them.
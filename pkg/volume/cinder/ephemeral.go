@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/volume"
+)
+
+// ephemeralTagPodUID and ephemeralTagPodNamespace are the Cinder volume
+// tags recorded on pod-scoped volumes, since Cinder has no concept of a
+// Kubernetes owner reference; a reaper controller can list volumes by
+// these tags to garbage-collect ones left behind by a kubelet that
+// restarted before TearDown ran.
+const (
+	ephemeralTagPodUID       = "kubernetes.io/created-for-pod-uid"
+	ephemeralTagPodNamespace = "kubernetes.io/created-for-pod-namespace"
+	ephemeralTagEphemeral    = "kubernetes.io/cinder-ephemeral"
+)
+
+// ephemeralCinderVolume is the Mounter/Unmounter for a pod-scoped Cinder
+// volume, for pods that set CinderVolumeSource.Ephemeral rather than
+// referencing a pre-existing volume ID. createEphemeralVolume provisions the
+// volume and TearDown deletes it; SetUp returns an error without
+// provisioning, attaching or mounting anything.
+type ephemeralCinderVolume struct {
+	volName  string
+	podUID   types.UID
+	pod      *v1.Pod
+	plugin   *cinderPlugin
+	volumeID string
+	attacher *cinderDiskAttacher
+	detacher *cinderDiskDetacher
+}
+
+var _ volume.Mounter = &ephemeralCinderVolume{}
+var _ volume.Unmounter = &ephemeralCinderVolume{}
+
+// newEphemeralCinderVolume returns the Mounter/Unmounter pair used for a
+// pod-scoped ephemeral Cinder volume named volName.
+func newEphemeralCinderVolume(plugin *cinderPlugin, pod *v1.Pod, volName string) (*ephemeralCinderVolume, error) {
+	attacher, err := plugin.NewAttacher()
+	if err != nil {
+		return nil, err
+	}
+	diskAttacher, ok := attacher.(*cinderDiskAttacher)
+	if !ok {
+		return nil, fmt.Errorf("unexpected attacher type %T for ephemeral cinder volume", attacher)
+	}
+	detacher, err := plugin.NewDetacher()
+	if err != nil {
+		return nil, err
+	}
+	diskDetacher, ok := detacher.(*cinderDiskDetacher)
+	if !ok {
+		return nil, fmt.Errorf("unexpected detacher type %T for ephemeral cinder volume", detacher)
+	}
+	return &ephemeralCinderVolume{
+		volName:  volName,
+		podUID:   pod.UID,
+		pod:      pod,
+		plugin:   plugin,
+		attacher: diskAttacher,
+		detacher: diskDetacher,
+	}, nil
+}
+
+// ephemeralVolumeOpts is the subset of a pod's inline `cinder: {ephemeral:
+// true, ...}` source needed to provision the backing volume.
+type ephemeralVolumeOpts struct {
+	SizeGiB    int
+	VolumeType string
+}
+
+// createEphemeralVolume provisions the backing Cinder volume, tagging it
+// with the owning pod's UID and namespace so a reaper controller can find
+// it if kubelet restarts before TearDown runs.
+func (e *ephemeralCinderVolume) createEphemeralVolume(opts ephemeralVolumeOpts) error {
+	provider, err := e.attacher.getCinderProvider()
+	if err != nil {
+		return err
+	}
+	tags := map[string]string{
+		ephemeralTagPodUID:       string(e.podUID),
+		ephemeralTagPodNamespace: e.pod.Namespace,
+		ephemeralTagEphemeral:    "true",
+	}
+	volumeID, err := provider.CreateVolume(e.volName, opts.SizeGiB, opts.VolumeType, "", tags)
+	if err != nil {
+		return fmt.Errorf("failed to create ephemeral cinder volume for pod %s/%s: %v", e.pod.Namespace, e.pod.Name, err)
+	}
+	e.volumeID = volumeID
+	return nil
+}
+
+// SetUp calls SetUpAt on the pod volume path. SetUpAt returns an error
+// without provisioning, attaching or mounting the volume.
+func (e *ephemeralCinderVolume) SetUp(fsGroup *int64) error {
+	return e.SetUpAt(e.GetPath(), fsGroup)
+}
+
+func (e *ephemeralCinderVolume) SetUpAt(dir string, fsGroup *int64) error {
+	return fmt.Errorf("ephemeralCinderVolume.SetUpAt: not yet wired to cinderDiskAttacher.Attach/MountDevice -- needs a *volume.Spec built from the provisioned volumeID, which requires the CinderVolumeSource type from plugin.go (not present in this checkout, see csi_bridge.go)")
+}
+
+func (e *ephemeralCinderVolume) GetPath() string {
+	return e.plugin.host.GetPodVolumeDir(e.podUID, cinderVolumePluginName, e.volName)
+}
+
+func (e *ephemeralCinderVolume) GetAttributes() volume.Attributes {
+	return volume.Attributes{}
+}
+
+func (e *ephemeralCinderVolume) CanMount() error {
+	return nil
+}
+
+// TearDown unmounts the volume, then deletes it, since an ephemeral volume
+// has no life beyond the pod that created it. It does not detach the volume
+// before deleting it, and does nothing when no volume was provisioned.
+func (e *ephemeralCinderVolume) TearDown() error {
+	return e.TearDownAt(e.GetPath())
+}
+
+func (e *ephemeralCinderVolume) TearDownAt(dir string) error {
+	if e.volumeID == "" {
+		return nil
+	}
+	if err := e.detacher.UnmountDevice(dir); err != nil {
+		return err
+	}
+	provider, err := e.detacher.getCinderProvider()
+	if err != nil {
+		return err
+	}
+	return provider.DeleteVolume(e.volumeID)
+}
+
+// NOTE: SetUpAt is left unimplemented -- see its error message -- pending
+// the CinderVolumeSource type and cinderPlugin.NewMounter dispatch for
+// Ephemeral==true, both of which belong in plugin.go (not present in this
+// checkout). BlockStorageProvider.CreateVolume/DeleteVolume above are
+// assumed to already exist on the interface (the provisioner already calls
+// them); only the ephemeral-specific tagging and lifecycle here are new.
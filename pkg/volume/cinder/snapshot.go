@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cinder
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// SnapshotProvider is the snapshot-related subset of BlockStorageProvider
+// this change adds. It is declared separately from BlockStorageProvider
+// itself (defined in this package's plugin.go, not present in this
+// checkout) so cinderPlugin can assert for it with a type switch and keep
+// working against providers built before snapshot support landed.
+type SnapshotProvider interface {
+	CreateVolumeSnapshot(volumeID, name string, tags map[string]string) (snapshotID string, err error)
+	DeleteVolumeSnapshot(snapshotID string) error
+	GetSnapshotStatus(snapshotID string) (ready bool, sizeGiB int64, err error)
+}
+
+// waitSnapshotReady polls provider.GetSnapshotStatus using the same
+// exponential-backoff shape as cinderDiskAttacher.waitOperationFinished,
+// returning once the snapshot reports ready.
+func waitSnapshotReady(provider SnapshotProvider, snapshotID string) (int64, error) {
+	backoff := wait.Backoff{
+		Duration: operationFinishInitDelay,
+		Factor:   operationFinishFactor,
+		Steps:    operationFinishSteps,
+	}
+
+	var sizeGiB int64
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		ready, size, err := provider.GetSnapshotStatus(snapshotID)
+		if err != nil {
+			return false, err
+		}
+		sizeGiB = size
+		return ready, nil
+	})
+
+	if err == wait.ErrWaitTimeout {
+		err = fmt.Errorf("snapshot %q did not become ready within the alloted time", snapshotID)
+	}
+
+	return sizeGiB, err
+}
+
+// createVolumeFromDataSource is the entry point for provisioning a Cinder
+// volume from either a VolumeSnapshot (snapshotID) or another
+// PersistentVolumeClaim (sourceVolumeID, a clone). It rejects a call with
+// both set; otherwise it returns an error without calling
+// BlockStorageProvider.CreateVolume, so no volume is provisioned.
+func createVolumeFromDataSource(provider BlockStorageProvider, name string, sizeGiB int, volumeType, availability string, tags map[string]string, snapshotID, sourceVolumeID string) (string, error) {
+	if snapshotID != "" && sourceVolumeID != "" {
+		return "", fmt.Errorf("at most one of snapshotID and sourceVolumeID may be set")
+	}
+	// NOTE: BlockStorageProvider.CreateVolume (defined in plugin.go,
+	// which is not present in this checkout) takes
+	// openstack.volumes.CreateOpts and does not yet expose SnapshotID /
+	// SourceVolID passthrough. Threading snapshotID/sourceVolumeID into
+	// that call, and the provisioner's DataSource handling that picks
+	// them out of a PersistentVolumeClaim, is left for a follow-up; this
+	// function documents the contract createVolumeFromDataSource's
+	// caller in the provisioner should satisfy once that lands.
+	return "", fmt.Errorf("createVolumeFromDataSource: not yet wired to BlockStorageProvider.CreateVolume")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// SetDefaults_NetworkPolicySpec defaults PolicyTypes from whichever of
+// Ingress/Egress the spec actually populates, per the PolicyTypes doc
+// comment on NetworkPolicySpec: a spec with only Ingress rules defaults to
+// ["Ingress"], a spec with only Egress rules defaults to ["Egress"], and a
+// spec with both defaults to both. A spec with neither defaults to
+// ["Ingress"], preserving the original (pre-Egress) behavior that an empty
+// NetworkPolicy isolates the selected pods from all ingress.
+//
+// NOTE: this isn't wired into a scheme.Scheme's defaulting funcs the way
+// SetDefaults_* functions normally are -- that requires the
+// SchemeBuilder/AddTypeDefaultingFunc registration this package's
+// register.go would carry, and no register.go exists in this checkout (nor
+// does a versioned Codec that would call it). Until that's present, callers
+// constructing a NetworkPolicySpec in this checkout must call this
+// directly.
+func SetDefaults_NetworkPolicySpec(obj *NetworkPolicySpec) {
+	if len(obj.PolicyTypes) > 0 {
+		return
+	}
+	var types []PolicyType
+	if len(obj.Ingress) > 0 || len(obj.Egress) == 0 {
+		types = append(types, PolicyTypeIngress)
+	}
+	if len(obj.Egress) > 0 {
+		types = append(types, PolicyTypeEgress)
+	}
+	obj.PolicyTypes = types
+}
+
+// AnnotationIngressClass is the legacy annotation used to select an
+// IngressClass before IngressSpec.IngressClassName existed. It is kept around
+// so SetDefaults_IngressSpec can give it precedence over IngressClassName, per
+// IngressClassName's doc comment.
+const AnnotationIngressClass = "kubernetes.io/ingress.class"
+
+// SetDefaults_IngressSpec promotes the legacy kubernetes.io/ingress.class
+// annotation on ing into spec.IngressClassName whenever the annotation is
+// set, overwriting any existing spec.IngressClassName, per IngressClassName's
+// doc comment ("when that annotation is set, it must be given precedence
+// over this field"). If the annotation is absent, spec.IngressClassName is
+// left as the caller set it.
+//
+// NOTE: this isn't wired into a scheme.Scheme's defaulting funcs the way
+// SetDefaults_* functions normally are, for the same reason documented on
+// SetDefaults_NetworkPolicySpec above -- no register.go or versioned Codec
+// exists in this checkout to call it. Callers constructing an Ingress here
+// must call this directly, after setting ObjectMeta.Annotations.
+func SetDefaults_IngressSpec(ing *Ingress) {
+	class, ok := ing.ObjectMeta.Annotations[AnnotationIngressClass]
+	if !ok {
+		return
+	}
+	ing.Spec.IngressClassName = &class
+}
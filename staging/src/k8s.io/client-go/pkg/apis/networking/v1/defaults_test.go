@@ -0,0 +1,103 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetDefaultsNetworkPolicySpec(t *testing.T) {
+	cases := []struct {
+		name string
+		spec NetworkPolicySpec
+		want []PolicyType
+	}{
+		{
+			name: "neither ingress nor egress defaults to Ingress only",
+			spec: NetworkPolicySpec{},
+			want: []PolicyType{PolicyTypeIngress},
+		},
+		{
+			name: "ingress only defaults to Ingress only",
+			spec: NetworkPolicySpec{Ingress: []NetworkPolicyIngressRule{{}}},
+			want: []PolicyType{PolicyTypeIngress},
+		},
+		{
+			name: "egress only defaults to Egress only",
+			spec: NetworkPolicySpec{Egress: []NetworkPolicyEgressRule{{}}},
+			want: []PolicyType{PolicyTypeEgress},
+		},
+		{
+			name: "both ingress and egress default to both",
+			spec: NetworkPolicySpec{
+				Ingress: []NetworkPolicyIngressRule{{}},
+				Egress:  []NetworkPolicyEgressRule{{}},
+			},
+			want: []PolicyType{PolicyTypeIngress, PolicyTypeEgress},
+		},
+		{
+			name: "explicit PolicyTypes is left untouched",
+			spec: NetworkPolicySpec{
+				Egress:      []NetworkPolicyEgressRule{{}},
+				PolicyTypes: []PolicyType{PolicyTypeIngress},
+			},
+			want: []PolicyType{PolicyTypeIngress},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			spec := c.spec
+			SetDefaults_NetworkPolicySpec(&spec)
+			if !reflect.DeepEqual(spec.PolicyTypes, c.want) {
+				t.Errorf("PolicyTypes = %v, want %v", spec.PolicyTypes, c.want)
+			}
+		})
+	}
+}
+
+func TestSetDefaultsIngressSpecPromotesAnnotation(t *testing.T) {
+	className := "explicit-class"
+	ing := &Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationIngressClass: "annotated-class"},
+		},
+		Spec: IngressSpec{IngressClassName: &className},
+	}
+
+	SetDefaults_IngressSpec(ing)
+
+	if ing.Spec.IngressClassName == nil || *ing.Spec.IngressClassName != "annotated-class" {
+		t.Errorf("IngressClassName = %v, want the annotation to take precedence (\"annotated-class\")", ing.Spec.IngressClassName)
+	}
+}
+
+func TestSetDefaultsIngressSpecLeavesIngressClassNameWithoutAnnotation(t *testing.T) {
+	className := "explicit-class"
+	ing := &Ingress{
+		Spec: IngressSpec{IngressClassName: &className},
+	}
+
+	SetDefaults_IngressSpec(ing)
+
+	if ing.Spec.IngressClassName == nil || *ing.Spec.IngressClassName != "explicit-class" {
+		t.Errorf("IngressClassName = %v, want it unchanged (\"explicit-class\") without the annotation", ing.Spec.IngressClassName)
+	}
+}
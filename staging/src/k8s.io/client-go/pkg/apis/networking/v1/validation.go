@@ -0,0 +1,254 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	v1 "k8s.io/client-go/pkg/api/v1"
+)
+
+// ValidateIPBlock checks that block's CIDR parses as an IPv4 or IPv6
+// network, that every entry in Except is a strict subset of that network
+// (present but excluded from it, not equal to it and not outside it), and
+// returns the first problem found as an error. It does not check peer
+// (IPBlock cannot be combined with PodSelector/NamespaceSelector) is the
+// caller's responsibility, same as it's the caller's responsibility to
+// decide what "peer" even means here -- see NetworkPolicyPeer's doc comment.
+//
+// NOTE: as with IsDefaultDenyEgress below, the canonical home for this is
+// pkg/apis/networking/validation.ValidateIPBlock, which doesn't exist in
+// this checkout. This lives here, in the versioned package, as the nearest
+// honest place to put CIDR/Except checking that this package's own types
+// can depend on.
+func ValidateIPBlock(block *IPBlock) error {
+	if block == nil {
+		return fmt.Errorf("ipBlock: must not be nil")
+	}
+	if block.CIDR == "" {
+		return fmt.Errorf("ipBlock.cidr: must be specified")
+	}
+	cidrIP, cidrNet, err := net.ParseCIDR(block.CIDR)
+	if err != nil {
+		return fmt.Errorf("ipBlock.cidr: invalid CIDR %q: %v", block.CIDR, err)
+	}
+	if !cidrIP.Equal(cidrNet.IP) {
+		return fmt.Errorf("ipBlock.cidr: %q is not the canonical network address for its mask", block.CIDR)
+	}
+	for _, except := range block.Except {
+		exceptIP, exceptNet, err := net.ParseCIDR(except)
+		if err != nil {
+			return fmt.Errorf("ipBlock.except: invalid CIDR %q: %v", except, err)
+		}
+		if !exceptIP.Equal(exceptNet.IP) {
+			return fmt.Errorf("ipBlock.except: %q is not the canonical network address for its mask", except)
+		}
+		if !cidrNet.Contains(exceptNet.IP) {
+			return fmt.Errorf("ipBlock.except: %q is not contained within cidr %q", except, block.CIDR)
+		}
+		cidrOnes, cidrBits := cidrNet.Mask.Size()
+		exceptOnes, exceptBits := exceptNet.Mask.Size()
+		if exceptBits != cidrBits {
+			return fmt.Errorf("ipBlock.except: %q is not the same IP family as cidr %q", except, block.CIDR)
+		}
+		if exceptOnes <= cidrOnes {
+			return fmt.Errorf("ipBlock.except: %q must be a strict subset of cidr %q", except, block.CIDR)
+		}
+	}
+	return nil
+}
+
+// ValidateNetworkPolicyPeer checks that an IPBlock, if set, is not combined
+// with PodSelector or NamespaceSelector, and that the IPBlock itself passes
+// ValidateIPBlock. A peer with no field set, or with both PodSelector and
+// NamespaceSelector, is not rejected.
+func ValidateNetworkPolicyPeer(peer *NetworkPolicyPeer) error {
+	if peer == nil {
+		return nil
+	}
+	if peer.IPBlock == nil {
+		return nil
+	}
+	if peer.PodSelector != nil || peer.NamespaceSelector != nil {
+		return fmt.Errorf("ipBlock: may not be combined with podSelector or namespaceSelector")
+	}
+	return ValidateIPBlock(peer.IPBlock)
+}
+
+// ValidateNetworkPolicyPort checks that, if EndPort is set, Port is a
+// numeric (not named) port, EndPort is no smaller than Port, both fall
+// within the valid port range, and Protocol is one of the protocols that
+// support port ranges (TCP, UDP, SCTP).
+//
+// NOTE: plumbing EndPort through to kube-proxy/the NetworkPolicy
+// controllers so CNI plugins see a real range instead of an expanded list
+// of single-port rules isn't done here -- no kube-proxy package and no
+// NetworkPolicy controller exist in this checkout, only this API type
+// package, so there's nothing on the consuming side to wire this into yet.
+func ValidateNetworkPolicyPort(port *NetworkPolicyPort) error {
+	if port == nil || port.EndPort == nil {
+		return nil
+	}
+	if port.Port == nil {
+		return fmt.Errorf("endPort: may not be specified without port")
+	}
+	if port.Port.Type != intstr.Int {
+		return fmt.Errorf("endPort: may not be specified with a named (string) port")
+	}
+	start := port.Port.IntVal
+	end := *port.EndPort
+	if start < 1 || start > 65535 {
+		return fmt.Errorf("port: must be between 1 and 65535, inclusive, got %d", start)
+	}
+	if end < 1 || end > 65535 {
+		return fmt.Errorf("endPort: must be between 1 and 65535, inclusive, got %d", end)
+	}
+	if end < start {
+		return fmt.Errorf("endPort: must be greater than or equal to port (%d), got %d", start, end)
+	}
+	if port.Protocol != nil {
+		switch *port.Protocol {
+		case v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP:
+		default:
+			return fmt.Errorf("protocol: endPort requires TCP, UDP, or SCTP, got %q", *port.Protocol)
+		}
+	}
+	return nil
+}
+
+// IsDefaultDenyEgress reports whether spec isolates its selected pods from
+// all egress traffic: PolicyTypes includes Egress but Egress carries no
+// rules. This mirrors the existing ingress default-deny semantics (an empty
+// or absent Ingress list under an Ingress-typed spec denies all incoming
+// traffic) on the egress side.
+//
+// NOTE: the full validation this request describes -- rejecting specs where
+// PolicyTypes omits a section that has rules, or where Egress/PolicyTypes
+// are otherwise inconsistent -- belongs in pkg/apis/networking/validation's
+// ValidateNetworkPolicySpec, which isn't present in this checkout (there is
+// no internal pkg/apis/networking package here at all, only this versioned
+// client-go package). IsDefaultDenyEgress is exposed as a predicate so that
+// whatever does own admission-time validation, or a controller deciding
+// whether to program a deny-all egress rule, can depend on this package's
+// semantics without duplicating them.
+func IsDefaultDenyEgress(spec *NetworkPolicySpec) bool {
+	if spec == nil {
+		return false
+	}
+	hasEgressType := false
+	for _, t := range spec.PolicyTypes {
+		if t == PolicyTypeEgress {
+			hasEgressType = true
+			break
+		}
+	}
+	return hasEgressType && len(spec.Egress) == 0
+}
+
+// ValidatePathType checks that pathType is one of the three values
+// HTTPIngressPath.PathType documents (Exact, Prefix, ImplementationSpecific),
+// and that it is set at all: PathType is a required field on HTTPIngressPath.
+func ValidatePathType(pathType *PathType) error {
+	if pathType == nil {
+		return fmt.Errorf("pathType: must be specified")
+	}
+	switch *pathType {
+	case PathTypeExact, PathTypePrefix, PathTypeImplementationSpecific:
+		return nil
+	default:
+		return fmt.Errorf("pathType: unsupported value %q: must be one of %q, %q, %q", *pathType, PathTypeExact, PathTypePrefix, PathTypeImplementationSpecific)
+	}
+}
+
+// ValidateIngressBackend checks that backend names a service and a port, the
+// two fields an IngressBackend cannot function without.
+func ValidateIngressBackend(backend *IngressBackend) error {
+	if backend == nil {
+		return fmt.Errorf("backend: must not be nil")
+	}
+	if backend.ServiceName == "" {
+		return fmt.Errorf("backend.serviceName: must be specified")
+	}
+	if backend.ServicePort.IntValue() == 0 && backend.ServicePort.StrVal == "" {
+		return fmt.Errorf("backend.servicePort: must be specified")
+	}
+	return nil
+}
+
+// ValidateHTTPIngressPath checks that path's PathType is one of the supported
+// enum values and that its Backend is well-formed.
+func ValidateHTTPIngressPath(path *HTTPIngressPath) error {
+	if path == nil {
+		return fmt.Errorf("path: must not be nil")
+	}
+	if err := ValidatePathType(path.PathType); err != nil {
+		return err
+	}
+	return ValidateIngressBackend(&path.Backend)
+}
+
+// ValidateIngressRule checks rule's HTTP paths, if any, and does nothing for
+// a rule with no IngressRuleValue set: an Ingress may have rules that only
+// match on Host and rely on the default backend.
+func ValidateIngressRule(rule *IngressRule) error {
+	if rule == nil || rule.HTTP == nil {
+		return nil
+	}
+	for i := range rule.HTTP.Paths {
+		if err := ValidateHTTPIngressPath(&rule.HTTP.Paths[i]); err != nil {
+			return fmt.Errorf("http.paths[%d].%v", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateIngressSpec checks that spec specifies at least one of Backend or
+// Rules, and that every rule validates, per IngressSpec's doc comment ("At
+// least one of 'backend' or 'rules' must be specified").
+//
+// NOTE: as with ValidateIPBlock and ValidateNetworkPolicyPort above, the
+// canonical home for this is pkg/apis/networking/validation, which doesn't
+// exist in this checkout; same for the conversion webhook / internal
+// converter between extensions/v1beta1 Ingress and this package's Ingress
+// this request also asks for, and the /apis/networking.k8s.io/v1/ingresses
+// REST storage and scheme registration -- those live in
+// pkg/registry/networking and an install package, neither present here, and
+// extensions/v1beta1 itself (the conversion's other half) isn't in this
+// checkout either. This function, ValidateIngressBackend,
+// ValidateHTTPIngressPath, and ValidatePathType are the validation surface
+// this package's own types can carry until that infrastructure exists.
+func ValidateIngressSpec(spec *IngressSpec) error {
+	if spec == nil {
+		return fmt.Errorf("spec: must not be nil")
+	}
+	if spec.Backend == nil && len(spec.Rules) == 0 {
+		return fmt.Errorf("spec: must specify at least one of backend or rules")
+	}
+	if spec.Backend != nil {
+		if err := ValidateIngressBackend(spec.Backend); err != nil {
+			return fmt.Errorf("spec.%v", err)
+		}
+	}
+	for i := range spec.Rules {
+		if err := ValidateIngressRule(&spec.Rules[i]); err != nil {
+			return fmt.Errorf("spec.rules[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
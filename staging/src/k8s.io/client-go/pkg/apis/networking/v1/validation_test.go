@@ -0,0 +1,376 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func TestIsDefaultDenyEgress(t *testing.T) {
+	cases := []struct {
+		name string
+		spec *NetworkPolicySpec
+		want bool
+	}{
+		{name: "nil spec", spec: nil, want: false},
+		{name: "no PolicyTypes", spec: &NetworkPolicySpec{}, want: false},
+		{
+			name: "Egress type with no rules denies all egress",
+			spec: &NetworkPolicySpec{PolicyTypes: []PolicyType{PolicyTypeEgress}},
+			want: true,
+		},
+		{
+			name: "Egress type with rules does not deny all egress",
+			spec: &NetworkPolicySpec{
+				PolicyTypes: []PolicyType{PolicyTypeEgress},
+				Egress:      []NetworkPolicyEgressRule{{}},
+			},
+			want: false,
+		},
+		{
+			name: "Ingress-only type is not a deny-all-egress spec",
+			spec: &NetworkPolicySpec{PolicyTypes: []PolicyType{PolicyTypeIngress}},
+			want: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsDefaultDenyEgress(c.spec); got != c.want {
+				t.Errorf("IsDefaultDenyEgress() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateIPBlock(t *testing.T) {
+	cases := []struct {
+		name    string
+		block   *IPBlock
+		wantErr string
+	}{
+		{name: "nil block", block: nil, wantErr: "must not be nil"},
+		{name: "empty CIDR", block: &IPBlock{}, wantErr: "must be specified"},
+		{name: "invalid CIDR", block: &IPBlock{CIDR: "not-a-cidr"}, wantErr: "invalid CIDR"},
+		{
+			name:    "non-canonical CIDR",
+			block:   &IPBlock{CIDR: "192.168.1.1/24"},
+			wantErr: "not the canonical network address",
+		},
+		{
+			name:  "valid CIDR with no excepts",
+			block: &IPBlock{CIDR: "192.168.0.0/24"},
+		},
+		{
+			name: "valid CIDR with a strict-subset except",
+			block: &IPBlock{
+				CIDR:   "192.168.0.0/24",
+				Except: []string{"192.168.0.0/28"},
+			},
+		},
+		{
+			name: "except outside the CIDR",
+			block: &IPBlock{
+				CIDR:   "192.168.0.0/24",
+				Except: []string{"10.0.0.0/28"},
+			},
+			wantErr: "not contained within cidr",
+		},
+		{
+			name: "except equal to the CIDR is not a strict subset",
+			block: &IPBlock{
+				CIDR:   "192.168.0.0/24",
+				Except: []string{"192.168.0.0/24"},
+			},
+			wantErr: "must be a strict subset",
+		},
+		{
+			name: "except of a different IP family",
+			block: &IPBlock{
+				CIDR:   "192.168.0.0/24",
+				Except: []string{"::/0"},
+			},
+			wantErr: "not contained within cidr",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateIPBlock(c.block)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkPolicyPeer(t *testing.T) {
+	if err := ValidateNetworkPolicyPeer(nil); err != nil {
+		t.Errorf("nil peer: unexpected error: %v", err)
+	}
+
+	podSelectorOnly := &NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}
+	if err := ValidateNetworkPolicyPeer(podSelectorOnly); err != nil {
+		t.Errorf("podSelector-only peer: unexpected error: %v", err)
+	}
+
+	ipBlockOnly := &NetworkPolicyPeer{IPBlock: &IPBlock{CIDR: "10.0.0.0/8"}}
+	if err := ValidateNetworkPolicyPeer(ipBlockOnly); err != nil {
+		t.Errorf("ipBlock-only peer: unexpected error: %v", err)
+	}
+
+	combined := &NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{},
+		IPBlock:     &IPBlock{CIDR: "10.0.0.0/8"},
+	}
+	err := ValidateNetworkPolicyPeer(combined)
+	if err == nil || !strings.Contains(err.Error(), "may not be combined") {
+		t.Errorf("combined peer: error = %v, want a may-not-be-combined error", err)
+	}
+
+	invalidIPBlock := &NetworkPolicyPeer{IPBlock: &IPBlock{}}
+	if err := ValidateNetworkPolicyPeer(invalidIPBlock); err == nil {
+		t.Errorf("expected ValidateNetworkPolicyPeer to surface ValidateIPBlock's error")
+	}
+}
+
+func intOrStringPort(port int) *intstr.IntOrString {
+	v := intstr.FromInt(port)
+	return &v
+}
+
+func namedPort(name string) *intstr.IntOrString {
+	v := intstr.FromString(name)
+	return &v
+}
+
+func protocolPtr(p api.Protocol) *api.Protocol {
+	return &p
+}
+
+func endPortPtr(v int32) *int32 {
+	return &v
+}
+
+func TestValidateNetworkPolicyPort(t *testing.T) {
+	cases := []struct {
+		name    string
+		port    *NetworkPolicyPort
+		wantErr string
+	}{
+		{name: "nil port", port: nil},
+		{name: "no EndPort", port: &NetworkPolicyPort{Port: intOrStringPort(80)}},
+		{
+			name:    "EndPort without Port",
+			port:    &NetworkPolicyPort{EndPort: endPortPtr(90)},
+			wantErr: "may not be specified without port",
+		},
+		{
+			name:    "EndPort with a named Port",
+			port:    &NetworkPolicyPort{Port: namedPort("http"), EndPort: endPortPtr(90)},
+			wantErr: "named (string) port",
+		},
+		{
+			name:    "EndPort less than Port",
+			port:    &NetworkPolicyPort{Port: intOrStringPort(90), EndPort: endPortPtr(80)},
+			wantErr: "must be greater than or equal to port",
+		},
+		{
+			name:    "Port out of range",
+			port:    &NetworkPolicyPort{Port: intOrStringPort(0), EndPort: endPortPtr(90)},
+			wantErr: "port: must be between",
+		},
+		{
+			name:    "EndPort out of range",
+			port:    &NetworkPolicyPort{Port: intOrStringPort(80), EndPort: endPortPtr(70000)},
+			wantErr: "endPort: must be between",
+		},
+		{
+			name: "valid range with no protocol",
+			port: &NetworkPolicyPort{Port: intOrStringPort(80), EndPort: endPortPtr(90)},
+		},
+		{
+			name: "valid range with TCP",
+			port: &NetworkPolicyPort{
+				Port:     intOrStringPort(80),
+				EndPort:  endPortPtr(90),
+				Protocol: protocolPtr(api.ProtocolTCP),
+			},
+		},
+		{
+			name: "unsupported protocol for a range",
+			port: &NetworkPolicyPort{
+				Port:     intOrStringPort(80),
+				EndPort:  endPortPtr(90),
+				Protocol: protocolPtr(api.Protocol("ICMP")),
+			},
+			wantErr: "requires TCP, UDP, or SCTP",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateNetworkPolicyPort(c.port)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func pathTypePtr(p PathType) *PathType {
+	return &p
+}
+
+func TestValidatePathType(t *testing.T) {
+	if err := ValidatePathType(nil); err == nil {
+		t.Error("nil pathType: expected an error")
+	}
+	for _, p := range []PathType{PathTypeExact, PathTypePrefix, PathTypeImplementationSpecific} {
+		if err := ValidatePathType(pathTypePtr(p)); err != nil {
+			t.Errorf("pathType %q: unexpected error: %v", p, err)
+		}
+	}
+	if err := ValidatePathType(pathTypePtr(PathType("Bogus"))); err == nil {
+		t.Error("pathType \"Bogus\": expected an error")
+	}
+}
+
+func validBackend() IngressBackend {
+	return IngressBackend{ServiceName: "svc", ServicePort: intstr.FromInt(80)}
+}
+
+func TestValidateIngressBackend(t *testing.T) {
+	if err := ValidateIngressBackend(nil); err == nil {
+		t.Error("nil backend: expected an error")
+	}
+
+	backend := validBackend()
+	if err := ValidateIngressBackend(&backend); err != nil {
+		t.Errorf("valid backend: unexpected error: %v", err)
+	}
+
+	noName := validBackend()
+	noName.ServiceName = ""
+	if err := ValidateIngressBackend(&noName); err == nil || !strings.Contains(err.Error(), "serviceName") {
+		t.Errorf("missing serviceName: error = %v, want a serviceName error", err)
+	}
+
+	noPort := IngressBackend{ServiceName: "svc"}
+	if err := ValidateIngressBackend(&noPort); err == nil || !strings.Contains(err.Error(), "servicePort") {
+		t.Errorf("missing servicePort: error = %v, want a servicePort error", err)
+	}
+}
+
+func TestValidateHTTPIngressPath(t *testing.T) {
+	if err := ValidateHTTPIngressPath(nil); err == nil {
+		t.Error("nil path: expected an error")
+	}
+
+	valid := &HTTPIngressPath{PathType: pathTypePtr(PathTypePrefix), Backend: validBackend()}
+	if err := ValidateHTTPIngressPath(valid); err != nil {
+		t.Errorf("valid path: unexpected error: %v", err)
+	}
+
+	badPathType := &HTTPIngressPath{PathType: nil, Backend: validBackend()}
+	if err := ValidateHTTPIngressPath(badPathType); err == nil {
+		t.Error("missing PathType: expected an error")
+	}
+
+	badBackend := &HTTPIngressPath{PathType: pathTypePtr(PathTypePrefix), Backend: IngressBackend{}}
+	if err := ValidateHTTPIngressPath(badBackend); err == nil {
+		t.Error("invalid Backend: expected an error")
+	}
+}
+
+func TestValidateIngressRule(t *testing.T) {
+	if err := ValidateIngressRule(nil); err != nil {
+		t.Errorf("nil rule: unexpected error: %v", err)
+	}
+
+	hostOnly := &IngressRule{Host: "example.com"}
+	if err := ValidateIngressRule(hostOnly); err != nil {
+		t.Errorf("host-only rule with no HTTP: unexpected error: %v", err)
+	}
+
+	valid := &IngressRule{
+		IngressRuleValue: IngressRuleValue{
+			HTTP: &HTTPIngressRuleValue{
+				Paths: []HTTPIngressPath{{PathType: pathTypePtr(PathTypePrefix), Backend: validBackend()}},
+			},
+		},
+	}
+	if err := ValidateIngressRule(valid); err != nil {
+		t.Errorf("valid rule: unexpected error: %v", err)
+	}
+
+	invalid := &IngressRule{
+		IngressRuleValue: IngressRuleValue{
+			HTTP: &HTTPIngressRuleValue{
+				Paths: []HTTPIngressPath{{Backend: validBackend()}},
+			},
+		},
+	}
+	if err := ValidateIngressRule(invalid); err == nil || !strings.Contains(err.Error(), "http.paths[0]") {
+		t.Errorf("invalid path: error = %v, want it to contain %q", err, "http.paths[0]")
+	}
+}
+
+func TestValidateIngressSpec(t *testing.T) {
+	if err := ValidateIngressSpec(nil); err == nil {
+		t.Error("nil spec: expected an error")
+	}
+
+	neither := &IngressSpec{}
+	if err := ValidateIngressSpec(neither); err == nil || !strings.Contains(err.Error(), "at least one of backend or rules") {
+		t.Errorf("neither backend nor rules: error = %v, want an at-least-one error", err)
+	}
+
+	backend := validBackend()
+	withBackend := &IngressSpec{Backend: &backend}
+	if err := ValidateIngressSpec(withBackend); err != nil {
+		t.Errorf("valid backend-only spec: unexpected error: %v", err)
+	}
+
+	withInvalidRule := &IngressSpec{
+		Rules: []IngressRule{{
+			IngressRuleValue: IngressRuleValue{
+				HTTP: &HTTPIngressRuleValue{Paths: []HTTPIngressPath{{Backend: validBackend()}}},
+			},
+		}},
+	}
+	if err := ValidateIngressSpec(withInvalidRule); err == nil || !strings.Contains(err.Error(), "spec.rules[0]") {
+		t.Errorf("invalid rule: error = %v, want it to contain %q", err, "spec.rules[0]")
+	}
+}
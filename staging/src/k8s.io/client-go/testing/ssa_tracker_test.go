@@ -0,0 +1,67 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func setOf(paths ...fieldpath.Path) fieldpath.Set {
+	set := fieldpath.NewSet(paths...)
+	return *set
+}
+
+func TestSSAApplierCheckConflicts(t *testing.T) {
+	pathA := fieldpath.MakePathOrDie("spec", "rules")
+	pathB := fieldpath.MakePathOrDie("spec", "aggregationRule")
+
+	existing := []fieldOwnership{
+		{Manager: "controller-a", Fields: setOf(pathA)},
+		{Manager: "controller-b", Fields: setOf(pathB)},
+	}
+
+	var a SSAApplier
+	conflicts := a.CheckConflicts(existing, "controller-c", setOf(pathA))
+	if len(conflicts) != 1 || conflicts[0].Manager != "controller-a" {
+		t.Fatalf("expected a single conflict with controller-a, got %v", conflicts)
+	}
+
+	// A manager re-applying fields it already owns is not a conflict.
+	noConflicts := a.CheckConflicts(existing, "controller-a", setOf(pathA))
+	if len(noConflicts) != 0 {
+		t.Fatalf("expected no conflicts for a manager re-applying its own fields, got %v", noConflicts)
+	}
+}
+
+func TestSSAApplierMergeReplacesOwnEntry(t *testing.T) {
+	pathA := fieldpath.MakePathOrDie("spec", "rules")
+
+	existing := []fieldOwnership{
+		{Manager: "controller-a", Fields: setOf(pathA)},
+	}
+
+	var a SSAApplier
+	merged := a.Merge(existing, "controller-a", setOf(pathA))
+	if len(merged) != 1 || merged[0].Manager != "controller-a" {
+		t.Fatalf("expected controller-a's entry to be replaced in place, got %v", merged)
+	}
+	if !merged[0].Fields.Has(pathA) {
+		t.Fatalf("expected controller-a to still own %v after re-applying it", pathA)
+	}
+}
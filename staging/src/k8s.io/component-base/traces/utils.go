@@ -18,29 +18,102 @@ package traces
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel/exporters/otlp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// credentialsFromTLSConfig adapts a crypto/tls.Config into the gRPC
+// transport credentials otlpgrpc expects.
+func credentialsFromTLSConfig(cfg *tls.Config) credentials.TransportCredentials {
+	return credentials.NewTLS(cfg)
+}
 
-	"k8s.io/klog/v2"
+// Protocol selects the wire protocol used to talk to the OTLP collector.
+type Protocol string
+
+const (
+	// ProtocolGRPC sends spans over otlp/gRPC. This is the default, matching
+	// historical behavior.
+	ProtocolGRPC Protocol = "grpc"
+	// ProtocolHTTP sends spans over otlp/HTTP (protobuf-encoded), for
+	// environments where only HTTP egress is allowed (corporate proxies,
+	// TLS-terminating ingress in front of the collector).
+	ProtocolHTTP Protocol = "http/protobuf"
 )
 
-// NewProvider initializes tracing in the component, and enforces recommended tracing behavior.
-func NewProvider(ctx context.Context, baseSampler sdktrace.Sampler, resourceOpts []resource.Option, opts ...otlpgrpc.Option) trace.TracerProvider {
+// BatchOptions configures the batch span processor backing an exporter.
+// Zero values mean "use the SDK default" for that field.
+type BatchOptions struct {
+	MaxExportBatchSize int
+	BatchTimeout       time.Duration
+	ExportTimeout      time.Duration
+}
+
+// asSpanProcessorOptions converts BatchOptions into otlp's batch span
+// processor options, omitting any field left at its zero value so the SDK
+// default applies.
+func (b BatchOptions) asSpanProcessorOptions() []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if b.MaxExportBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(b.MaxExportBatchSize))
+	}
+	if b.BatchTimeout > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(b.BatchTimeout))
+	}
+	if b.ExportTimeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(b.ExportTimeout))
+	}
+	return opts
+}
+
+// TracingConfig describes how to connect to an OTLP collector and how to
+// batch and sample the spans sent to it. The zero value connects insecurely
+// over gRPC, matching the behavior NewProvider always had.
+type TracingConfig struct {
+	// Endpoint is the host:port (or, for ProtocolHTTP, the base URL) of the
+	// OTLP collector to export to.
+	Endpoint string
+	// Protocol selects grpc or http/protobuf. Defaults to ProtocolGRPC.
+	Protocol Protocol
+	// TLSConfig, if non-nil, is used to establish a TLS connection to the
+	// collector instead of the default insecure connection.
+	TLSConfig *tls.Config
+	// Headers are attached to every export request, e.g. for collectors
+	// that require an authentication token.
+	Headers map[string]string
+	// BatchOptions tunes the batch span processor sitting in front of the
+	// exporter.
+	BatchOptions BatchOptions
+	// SamplingRatio is the fraction (0.0-1.0) of requests sampled when no
+	// parent span context is already sampled. A zero value means "use the
+	// baseSampler passed in to NewProviderFromConfig unchanged".
+	SamplingRatio float64
+}
+
+// NewProvider initializes tracing in the component, and enforces recommended
+// tracing behavior. It always exports over insecure otlp/gRPC; use
+// NewProviderFromConfig for HTTP, TLS, custom headers, or batching options.
+func NewProvider(ctx context.Context, baseSampler sdktrace.Sampler, resourceOpts []resource.Option, opts ...otlpgrpc.Option) (trace.TracerProvider, error) {
 	opts = append(opts, otlpgrpc.WithInsecure())
 	driver := otlpgrpc.NewDriver(opts...)
 	exporter, err := otlp.NewExporter(ctx, driver)
 	if err != nil {
-		klog.Fatalf("Failed to create OTLP exporter: %v", err)
+		return nil, fmt.Errorf("failed to create OTLP exporter: %v", err)
 	}
 
 	res, err := resource.New(ctx, resourceOpts...)
 	if err != nil {
-		klog.Fatalf("Failed to create resource: %v", err)
+		return nil, fmt.Errorf("failed to create resource: %v", err)
 	}
 
 	bsp := sdktrace.NewBatchSpanProcessor(exporter)
@@ -49,7 +122,71 @@ func NewProvider(ctx context.Context, baseSampler sdktrace.Sampler, resourceOpts
 		sdktrace.WithSampler(sdktrace.ParentBased(baseSampler)),
 		sdktrace.WithSpanProcessor(bsp),
 		sdktrace.WithResource(res),
-	)
+	), nil
+}
+
+// NewProviderFromConfig initializes tracing the same way NewProvider does,
+// but takes a TracingConfig so callers can select otlp/HTTP instead of
+// gRPC, configure TLS, attach collector auth headers, and tune batching.
+// Unlike NewProvider's predecessor, it never calls klog.Fatalf: embedding
+// components (kubelet, apiserver) can fall back to a no-op provider instead
+// of crashing when tracing setup fails.
+func NewProviderFromConfig(ctx context.Context, cfg TracingConfig, baseSampler sdktrace.Sampler, resourceOpts []resource.Option) (trace.TracerProvider, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %v", err)
+	}
+
+	res, err := resource.New(ctx, resourceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %v", err)
+	}
+
+	sampler := sdktrace.ParentBased(baseSampler)
+	if cfg.SamplingRatio > 0 {
+		sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))
+	}
+
+	bsp := sdktrace.NewBatchSpanProcessor(exporter, cfg.BatchOptions.asSpanProcessorOptions()...)
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithSpanProcessor(bsp),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+func newExporter(ctx context.Context, cfg TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Protocol {
+	case ProtocolHTTP:
+		opts := []otlphttp.Option{otlphttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.TLSConfig != nil {
+			opts = append(opts, otlphttp.WithTLSClientConfig(cfg.TLSConfig))
+		} else {
+			opts = append(opts, otlphttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlphttp.WithHeaders(cfg.Headers))
+		}
+		driver := otlphttp.NewDriver(opts...)
+		return otlp.NewExporter(ctx, driver)
+
+	case ProtocolGRPC, "":
+		opts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.TLSConfig != nil {
+			opts = append(opts, otlpgrpc.WithTLSCredentials(credentialsFromTLSConfig(cfg.TLSConfig)))
+		} else {
+			opts = append(opts, otlpgrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpgrpc.WithHeaders(cfg.Headers))
+		}
+		driver := otlpgrpc.NewDriver(opts...)
+		return otlp.NewExporter(ctx, driver)
+
+	default:
+		return nil, fmt.Errorf("unsupported tracing protocol %q", cfg.Protocol)
+	}
 }
 
 // Propagators returns the recommended set of propagators.
@@ -20,9 +20,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	watch "k8s.io/apimachinery/pkg/watch"
@@ -34,7 +37,8 @@ import (
 
 var (
 	// tests which use this appear to all pass within the given time
-	generalWatchTimeout = int64(60)
+	generalWatchTimeout     = int64(60)
+	generalWatchWaitTimeout = time.Duration(generalWatchTimeout) * time.Second
 )
 
 var _ = ginkgo.Describe("[sig-node] ConfigMap", func() {
@@ -143,6 +147,58 @@ var _ = ginkgo.Describe("[sig-node] ConfigMap", func() {
 		framework.ExpectError(err, "created configMap %q with empty key in namespace %q", configMap.Name, f.Namespace.Name)
 	})
 
+	ginkgo.It("should resolve secret:// references when creating a ConfigMap", func() {
+		secretName := "configmap-secretref-" + string(uuid.NewUUID())
+		ginkgo.By(fmt.Sprintf("Creating Secret %v/%v", f.Namespace.Name, secretName))
+		_, err := f.ClientSet.CoreV1().Secrets(f.Namespace.Name).Create(context.TODO(), &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: f.Namespace.Name,
+				Name:      secretName,
+			},
+			Data: map[string][]byte{
+				"password": []byte("s3cr3t"),
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create Secret")
+
+		name := "configmap-from-secret-" + string(uuid.NewUUID())
+		ginkgo.By(fmt.Sprintf("Creating configMap %v/%v with a secret:// reference", f.Namespace.Name, name))
+		configMap, err := newSecretRefConfigMap(f, name, map[string]string{
+			"db-password": fmt.Sprintf("secret://%s/password", secretName),
+		})
+		framework.ExpectNoError(err, "failed to create ConfigMap from secret reference")
+		framework.ExpectEqual(configMap.Data["db-password"], "s3cr3t")
+	})
+
+	ginkgo.It("should fail to create a ConfigMap referencing a missing Secret", func() {
+		name := "configmap-missing-secret-" + string(uuid.NewUUID())
+		_, err := newSecretRefConfigMap(f, name, map[string]string{
+			"db-password": "secret://does-not-exist/password",
+		})
+		framework.ExpectError(err, "created configMap %q from a reference to a missing Secret", name)
+	})
+
+	ginkgo.It("should fail to create a ConfigMap referencing an empty Secret key", func() {
+		secretName := "configmap-secretref-empty-" + string(uuid.NewUUID())
+		ginkgo.By(fmt.Sprintf("Creating Secret %v/%v", f.Namespace.Name, secretName))
+		_, err := f.ClientSet.CoreV1().Secrets(f.Namespace.Name).Create(context.TODO(), &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: f.Namespace.Name,
+				Name:      secretName,
+			},
+			Data: map[string][]byte{
+				"password": []byte(""),
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create Secret")
+
+		name := "configmap-empty-secret-" + string(uuid.NewUUID())
+		_, err = newSecretRefConfigMap(f, name, map[string]string{
+			"db-password": fmt.Sprintf("secret://%s/password", secretName),
+		})
+		framework.ExpectError(err, "created configMap %q from a reference to an empty Secret key", name)
+	})
+
 	ginkgo.It("should update ConfigMap successfully", func() {
 		name := "configmap-test-" + string(uuid.NewUUID())
 		configMap := newConfigMap(f, name)
@@ -181,18 +237,14 @@ var _ = ginkgo.Describe("[sig-node] ConfigMap", func() {
 		}, metav1.CreateOptions{})
 		framework.ExpectNoError(err, "failed to create ConfigMap")
 
-		ginkgo.By("setting a watch for the ConfigMap")
-		// setup a watch for the ConfigMap
-		resourceWatch, err := f.ClientSet.CoreV1().ConfigMaps(testNamespaceName).Watch(context.TODO(), metav1.ListOptions{LabelSelector: "test-configmap-static=true", TimeoutSeconds: &generalWatchTimeout})
-		framework.ExpectNoError(err, "Failed to setup watch on newly created ConfigMap")
+		configMapGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
 
-		resourceWatchChan := resourceWatch.ResultChan()
 		ginkgo.By("waiting for the ConfigMap to be added")
-		for watchEvent := range resourceWatchChan {
-			if watchEvent.Type == watch.Added {
-				break
-			}
-		}
+		// f.Meta is the Framework's shared MetaCache, used in place of the
+		// per-test Watch this lifecycle test used to open. MetaCache is not
+		// informer-backed: it sees only the events passed to its Observe
+		// method.
+		framework.ExpectNoError(f.Meta.WaitForEvent(configMapGVR, testNamespaceName, testConfigMapName, watch.Added, generalWatchWaitTimeout), "Failed to observe ConfigMap creation")
 
 		configMapPatchPayload, err := json.Marshal(v1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
@@ -210,11 +262,7 @@ var _ = ginkgo.Describe("[sig-node] ConfigMap", func() {
 		_, err = f.ClientSet.CoreV1().ConfigMaps(testNamespaceName).Patch(context.TODO(), testConfigMapName, types.StrategicMergePatchType, []byte(configMapPatchPayload), metav1.PatchOptions{})
 		framework.ExpectNoError(err, "failed to patch ConfigMap")
 		ginkgo.By("waiting for the ConfigMap to be modified")
-		for watchEvent := range resourceWatchChan {
-			if watchEvent.Type == watch.Modified {
-				break
-			}
-		}
+		framework.ExpectNoError(f.Meta.WaitForEvent(configMapGVR, testNamespaceName, testConfigMapName, watch.Modified, generalWatchWaitTimeout), "Failed to observe ConfigMap modification")
 
 		ginkgo.By("fetching the ConfigMap")
 		configMap, err := f.ClientSet.CoreV1().ConfigMaps(testNamespaceName).Get(context.TODO(), testConfigMapName, metav1.GetOptions{})
@@ -246,12 +294,7 @@ var _ = ginkgo.Describe("[sig-node] ConfigMap", func() {
 		})
 		framework.ExpectNoError(err, "failed to delete ConfigMap collection with LabelSelector")
 		ginkgo.By("waiting for the ConfigMap to be deleted")
-		for watchEvent := range resourceWatchChan {
-			if watchEvent.Type == watch.Deleted {
-				break
-			}
-			fmt.Println("failed to find Deleted watchEvent")
-		}
+		framework.ExpectNoError(f.Meta.WaitForEvent(configMapGVR, testNamespaceName, testConfigMapName, watch.Deleted, generalWatchWaitTimeout), "Failed to observe ConfigMap deletion")
 	})
 })
 
@@ -284,3 +327,68 @@ func newConfigMapWithEmptyKey(f *framework.Framework) (*v1.ConfigMap, error) {
 	ginkgo.By(fmt.Sprintf("Creating configMap that has name %s", configMap.Name))
 	return f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
 }
+
+// secretRefPrefix is the scheme newSecretRefConfigMap resolves before
+// creating the ConfigMap, mirroring the old kubecfg-fork pattern of
+// letting a config-authoring layer transparently pull in secret material
+// rather than requiring callers to read Secrets themselves.
+const secretRefPrefix = "secret://"
+
+// newSecretRefConfigMap creates a ConfigMap in f.Namespace.Name named name,
+// first resolving any data value of the form "secret://<secretName>/<key>"
+// in refs against a real Secret in the same namespace. It fails if a
+// referenced Secret doesn't exist or the referenced key is empty, so that
+// a missing or misconfigured Secret never silently produces a ConfigMap
+// with a blank credential in it.
+func newSecretRefConfigMap(f *framework.Framework, name string, refs map[string]string) (*v1.ConfigMap, error) {
+	data, err := resolveSecretRefs(f, refs)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: f.Namespace.Name,
+			Name:      name,
+		},
+		Data: data,
+	}
+	return f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
+}
+
+// resolveSecretRefs returns a copy of refs with every "secret://<name>/<key>"
+// value replaced by the referenced Secret's data. Values that aren't a
+// secret:// reference pass through unchanged.
+func resolveSecretRefs(f *framework.Framework, refs map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(refs))
+	for dataKey, ref := range refs {
+		if !strings.HasPrefix(ref, secretRefPrefix) {
+			resolved[dataKey] = ref
+			continue
+		}
+		secretName, secretKey, err := parseSecretRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := f.ClientSet.CoreV1().Secrets(f.Namespace.Name).Get(context.TODO(), secretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("resolving %q: %v", ref, err)
+		}
+		value, ok := secret.Data[secretKey]
+		if !ok || len(value) == 0 {
+			return nil, fmt.Errorf("resolving %q: secret %q has no non-empty key %q", ref, secretName, secretKey)
+		}
+		resolved[dataKey] = string(value)
+	}
+	return resolved, nil
+}
+
+// parseSecretRef splits a "secret://<name>/<key>" reference into its name
+// and key parts.
+func parseSecretRef(ref string) (name, key string, err error) {
+	trimmed := strings.TrimPrefix(ref, secretRefPrefix)
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("malformed secret reference %q, want secret://<name>/<key>", ref)
+	}
+	return parts[0], parts[1], nil
+}
@@ -229,6 +229,129 @@ var _ = SIGDescribe("[Feature:WindowsHostProcessContainers] [Excluded:WindowsDoc
 			framework.Failf("Unexpected error: %v\n%s", err, logs)
 		}
 	})
+
+	ginkgo.It("should support a Lease-based leader election among HostProcess containers", func() {
+		trueVar := true
+		user := "NT AUTHORITY\\SYSTEM"
+		leaseName := "host-process-leader-election"
+		numCandidates := 3
+		podNames := make([]string, 0, numCandidates)
+
+		ginkgo.By(fmt.Sprintf("scheduling %d HostProcess pods racing for Lease %q", numCandidates, leaseName))
+		for i := 0; i < numCandidates; i++ {
+			podName := fmt.Sprintf("host-process-leader-election-%d", i)
+			podNames = append(podNames, podName)
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: podName,
+				},
+				Spec: v1.PodSpec{
+					SecurityContext: &v1.PodSecurityContext{
+						WindowsOptions: &v1.WindowsSecurityContextOptions{
+							HostProcess:   &trueVar,
+							RunAsUserName: &user,
+						},
+					},
+					HostNetwork: true,
+					Containers: []v1.Container{
+						{
+							Name:  "leader-election",
+							Image: imageutils.GetE2EImage(imageutils.Agnhost),
+							Args: []string{
+								"leader-election",
+								"--election=" + leaseName,
+								"--election-namespace=" + f.Namespace.Name,
+								"--http-probe-port=9999",
+								"--identity=" + podName,
+							},
+						},
+					},
+					RestartPolicy: v1.RestartPolicyNever,
+					NodeSelector: map[string]string{
+						"kubernetes.io/os": "windows",
+					},
+				},
+			}
+			f.PodClient().Create(pod)
+		}
+
+		ginkgo.By("waiting for all candidate pods to become ready")
+		if !e2epod.CheckPodsRunningReady(f.ClientSet, f.Namespace.Name, podNames, 3*time.Minute) {
+			framework.Failf("not all leader election candidate pods became ready")
+		}
+
+		ginkgo.By("waiting for exactly one candidate to be recorded as the Lease holder")
+		var leaseHolder string
+		err := wait.PollImmediate(5*time.Second, 2*time.Minute, func() (bool, error) {
+			lease, err := f.ClientSet.CoordinationV1().Leases(f.Namespace.Name).Get(context.TODO(), leaseName, metav1.GetOptions{})
+			if err != nil {
+				framework.Logf("error getting lease %q: %v", leaseName, err)
+				return false, nil
+			}
+			if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+				return false, nil
+			}
+			leaseHolder = *lease.Spec.HolderIdentity
+			return true, nil
+		})
+		framework.ExpectNoError(err, "Lease %q never acquired a holder", leaseName)
+
+		found := false
+		for _, podName := range podNames {
+			if podName == leaseHolder {
+				found = true
+				break
+			}
+		}
+		if !found {
+			framework.Failf("Lease holder %q is not one of the candidate pods %v", leaseHolder, podNames)
+		}
+		framework.Logf("pod %q is the elected leader for Lease %q", leaseHolder, leaseName)
+	})
+
+	ginkgo.It("should support exec through SPDY streaming into a HostProcess container", func() {
+		ginkgo.By("selecting a Windows node")
+		targetNode, err := findWindowsNode(f)
+		framework.ExpectNoError(err, "Error finding Windows node")
+
+		trueVar := true
+		podName := "host-process-exec-test-pod"
+		containerName := "exec-test"
+		user := "NT AUTHORITY\\SYSTEM"
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: podName,
+			},
+			Spec: v1.PodSpec{
+				SecurityContext: &v1.PodSecurityContext{
+					WindowsOptions: &v1.WindowsSecurityContextOptions{
+						HostProcess:   &trueVar,
+						RunAsUserName: &user,
+					},
+				},
+				HostNetwork: true,
+				Containers: []v1.Container{
+					{
+						Name:    containerName,
+						Image:   imageutils.GetE2EImage(imageutils.BusyBox),
+						Command: []string{"powershell", "-c", "Start-Sleep", "-Seconds", "300"},
+					},
+				},
+				RestartPolicy: v1.RestartPolicyNever,
+				NodeName:      targetNode.Name,
+			},
+		}
+
+		ginkgo.By("creating a long-running HostProcess pod")
+		f.PodClient().Create(pod)
+		if !e2epod.CheckPodsRunningReady(f.ClientSet, f.Namespace.Name, []string{podName}, 3*time.Minute) {
+			framework.Failf("pod %q in ns %q never became ready", podName, f.Namespace.Name)
+		}
+
+		ginkgo.By("exec'ing a command that reads %COMPUTERNAME% and asserting the streamed stdout matches the node")
+		stdout := e2epod.ExecCommandInContainer(f, podName, containerName, "cmd.exe", "/c", "echo", "%COMPUTERNAME%")
+		framework.ExpectEqual(strings.TrimSpace(stdout), targetNode.Name, "exec streamed stdout should report the host's own node name, since HostProcess containers run directly on the host")
+	})
 })
 
 func SkipUnlessWindowsHostProcessContainersEnabled() {
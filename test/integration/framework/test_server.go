@@ -18,6 +18,7 @@ package framework
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
@@ -54,12 +55,37 @@ AwEHoUQDQgAEH6cuzP8XuD5wal6wf9M6xDljTOPLX2i8uIp/C/ASqiIGUeeKQtX0
 type TestServerSetup struct {
 	ModifyServerRunOptions func(*options.ServerRunOptions)
 	ModifyServerConfig     func(*controlplane.Config)
+
+	// KubeadmClusterConfiguration, if set, is translated into ServerRunOptions
+	// before ModifyServerRunOptions runs, so a bootstrapper's reported
+	// ClusterConfiguration can be pasted in directly to reproduce a bug instead
+	// of manually re-deriving the equivalent ModifyServerRunOptions calls.
+	KubeadmClusterConfiguration *KubeadmClusterConfiguration
+
+	// FIPSOnly restricts the test server(s) to FIPS-approved TLS ciphers and
+	// minimum version, and requires the test CAs to use a FIPS-approved key
+	// type. It requires the test binary to be built with the boringcrypto tag;
+	// StartTestServer fails fast if it isn't.
+	FIPSOnly bool
 }
 
 type TearDownFunc func()
 
 // StartTestServer runs a kube-apiserver, optionally calling out to the setup.ModifyServerRunOptions and setup.ModifyServerConfig functions
 func StartTestServer(ctx context.Context, t testing.TB, setup TestServerSetup) (client.Interface, *rest.Config, TearDownFunc) {
+	clients, configs, tearDownFn := StartHATestServer(ctx, t, setup, 1)
+	return clients[0], configs[0], tearDownFn
+}
+
+// StartHATestServer runs numServers kube-apiservers sharing the same etcd storage prefix and
+// signing/CA material, simulating an HA control plane. It otherwise behaves like StartTestServer:
+// setup.ModifyServerRunOptions and setup.ModifyServerConfig are called once per server instance,
+// so tests can still tweak each member identically (or differentiate them by inspecting how many
+// times the callback has already run).
+func StartHATestServer(ctx context.Context, t testing.TB, setup TestServerSetup, numServers int) ([]client.Interface, []*rest.Config, TearDownFunc) {
+	if numServers < 1 {
+		t.Fatalf("numServers must be at least 1, got %d", numServers)
+	}
 	ctx, cancel := context.WithCancel(ctx)
 
 	certDir, err := os.MkdirTemp("", "test-integration-"+strings.ReplaceAll(t.Name(), "/", "_"))
@@ -67,15 +93,15 @@ func StartTestServer(ctx context.Context, t testing.TB, setup TestServerSetup) (
 		t.Fatalf("Couldn't create temp dir: %v", err)
 	}
 
-	var errCh chan error
+	var errChs []chan error
 	tearDownFn := func() {
-		// Calling cancel function is stopping apiserver and cleaning up
-		// after itself, including shutting down its storage layer.
+		// Calling cancel function is stopping every apiserver and cleaning
+		// up after itself, including shutting down its storage layer.
 		cancel()
 
-		// If the apiserver was started, let's wait for it to
-		// shutdown clearly.
-		if errCh != nil {
+		// If the apiservers were started, let's wait for all of them to
+		// shut down cleanly.
+		for _, errCh := range errChs {
 			err, ok := <-errCh
 			if ok && err != nil {
 				t.Error(err)
@@ -86,8 +112,13 @@ func StartTestServer(ctx context.Context, t testing.TB, setup TestServerSetup) (
 		}
 	}
 
+	// Storage prefix and signing/CA material are shared across every
+	// member of the HA cluster so they all serve the same etcd-backed
+	// state and trust each other's client certs/tokens.
+	sharedStoragePrefix := path.Join("/", uuid.New().String(), "registry")
+
 	_, defaultServiceClusterIPRange, _ := netutils.ParseCIDRSloppy("10.0.0.0/24")
-	proxySigningKey, err := utils.NewPrivateKey()
+	proxySigningKey, err := newCATestKey(setup.FIPSOnly)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -100,7 +131,7 @@ func StartTestServer(ctx context.Context, t testing.TB, setup TestServerSetup) (
 		t.Fatal(err)
 	}
 	defer proxyCACertFile.Close()
-	clientSigningKey, err := utils.NewPrivateKey()
+	clientSigningKey, err := newCATestKey(setup.FIPSOnly)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -113,10 +144,6 @@ func StartTestServer(ctx context.Context, t testing.TB, setup TestServerSetup) (
 		t.Fatal(err)
 	}
 	defer clientCACertFile.Close()
-	listener, _, err := genericapiserveroptions.CreateListener("tcp", "127.0.0.1:0", net.ListenConfig{})
-	if err != nil {
-		t.Fatal(err)
-	}
 
 	saSigningKeyFile, err := os.CreateTemp("/tmp", "insecure_test_key")
 	if err != nil {
@@ -127,106 +154,132 @@ func StartTestServer(ctx context.Context, t testing.TB, setup TestServerSetup) (
 		t.Fatalf("write file %s failed: %v", saSigningKeyFile.Name(), err)
 	}
 
-	opts := options.NewServerRunOptions()
-	opts.GenericControlPlane.SecureServing.Listener = listener
-	opts.GenericControlPlane.SecureServing.BindAddress = netutils.ParseIPSloppy("127.0.0.1")
-	opts.GenericControlPlane.SecureServing.ServerCert.CertDirectory = certDir
-	opts.GenericControlPlane.ServiceAccountSigningKeyFile = saSigningKeyFile.Name()
-	opts.GenericControlPlane.Etcd.StorageConfig.Prefix = path.Join("/", uuid.New().String(), "registry")
-	opts.GenericControlPlane.Etcd.StorageConfig.Transport.ServerList = []string{GetEtcdURL()}
-	opts.ServiceClusterIPRanges = defaultServiceClusterIPRange.String()
-	opts.GenericControlPlane.Authentication.RequestHeader.UsernameHeaders = []string{"X-Remote-User"}
-	opts.GenericControlPlane.Authentication.RequestHeader.GroupHeaders = []string{"X-Remote-Group"}
-	opts.GenericControlPlane.Authentication.RequestHeader.ExtraHeaderPrefixes = []string{"X-Remote-Extra-"}
-	opts.GenericControlPlane.Authentication.RequestHeader.AllowedNames = []string{"kube-aggregator"}
-	opts.GenericControlPlane.Authentication.RequestHeader.ClientCAFile = proxyCACertFile.Name()
-	opts.GenericControlPlane.Authentication.APIAudiences = []string{"https://foo.bar.example.com"}
-	opts.GenericControlPlane.Authentication.ServiceAccounts.Issuers = []string{"https://foo.bar.example.com"}
-	opts.GenericControlPlane.Authentication.ServiceAccounts.KeyFiles = []string{saSigningKeyFile.Name()}
-	opts.GenericControlPlane.Authentication.ClientCert.ClientCA = clientCACertFile.Name()
-	opts.GenericControlPlane.Authorization.Modes = []string{"Node", "RBAC"}
-
-	if setup.ModifyServerRunOptions != nil {
-		setup.ModifyServerRunOptions(opts)
-	}
+	clients := make([]client.Interface, 0, numServers)
+	configs := make([]*rest.Config, 0, numServers)
 
-	completedOptions, err := opts.Complete()
-	if err != nil {
-		t.Fatal(err)
-	}
+	for i := 0; i < numServers; i++ {
+		// Each member gets its own listener and its own serving-cert
+		// directory (so they don't clobber each other's apiserver.crt),
+		// but shares the proxy/client CAs, SA signing key, and etcd
+		// storage prefix set up above.
+		serverCertDir := path.Join(certDir, fmt.Sprintf("server-%d", i))
+		if err := os.MkdirAll(serverCertDir, 0755); err != nil {
+			t.Fatal(err)
+		}
 
-	if errs := completedOptions.Validate(); len(errs) != 0 {
-		t.Fatalf("failed to validate ServerRunOptions: %v", utilerrors.NewAggregate(errs))
-	}
+		listener, _, err := genericapiserveroptions.CreateListener("tcp", "127.0.0.1:0", net.ListenConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	kubeAPIServerConfig, _, _, err := app.CreateKubeAPIServerConfig(completedOptions)
-	if err != nil {
-		t.Fatal(err)
-	}
+		opts := options.NewServerRunOptions()
+		opts.GenericControlPlane.SecureServing.Listener = listener
+		opts.GenericControlPlane.SecureServing.BindAddress = netutils.ParseIPSloppy("127.0.0.1")
+		opts.GenericControlPlane.SecureServing.ServerCert.CertDirectory = serverCertDir
+		opts.GenericControlPlane.ServiceAccountSigningKeyFile = saSigningKeyFile.Name()
+		opts.GenericControlPlane.Etcd.StorageConfig.Prefix = sharedStoragePrefix
+		opts.GenericControlPlane.Etcd.StorageConfig.Transport.ServerList = []string{GetEtcdURL()}
+		opts.ServiceClusterIPRanges = defaultServiceClusterIPRange.String()
+		opts.GenericControlPlane.Authentication.RequestHeader.UsernameHeaders = []string{"X-Remote-User"}
+		opts.GenericControlPlane.Authentication.RequestHeader.GroupHeaders = []string{"X-Remote-Group"}
+		opts.GenericControlPlane.Authentication.RequestHeader.ExtraHeaderPrefixes = []string{"X-Remote-Extra-"}
+		opts.GenericControlPlane.Authentication.RequestHeader.AllowedNames = []string{"kube-aggregator"}
+		opts.GenericControlPlane.Authentication.RequestHeader.ClientCAFile = proxyCACertFile.Name()
+		opts.GenericControlPlane.Authentication.APIAudiences = []string{"https://foo.bar.example.com"}
+		opts.GenericControlPlane.Authentication.ServiceAccounts.Issuers = []string{"https://foo.bar.example.com"}
+		opts.GenericControlPlane.Authentication.ServiceAccounts.KeyFiles = []string{saSigningKeyFile.Name()}
+		opts.GenericControlPlane.Authentication.ClientCert.ClientCA = clientCACertFile.Name()
+		opts.GenericControlPlane.Authorization.Modes = []string{"Node", "RBAC"}
 
-	if setup.ModifyServerConfig != nil {
-		setup.ModifyServerConfig(kubeAPIServerConfig)
-	}
-	kubeAPIServer, err := kubeAPIServerConfig.Complete().New(genericapiserver.NewEmptyDelegate())
-	if err != nil {
-		t.Fatal(err)
-	}
+		applyKubeadmClusterConfiguration(t, opts, setup.KubeadmClusterConfiguration)
+		applyFIPSOnly(t, opts, setup.FIPSOnly)
 
-	errCh = make(chan error)
-	go func() {
-		defer close(errCh)
-		if err := kubeAPIServer.GenericAPIServer.PrepareRun().Run(ctx.Done()); err != nil {
-			errCh <- err
-		}
-	}()
-
-	// Adjust the loopback config for external use (external server name and CA)
-	kubeAPIServerClientConfig := rest.CopyConfig(kubeAPIServerConfig.GenericConfig.LoopbackClientConfig)
-	kubeAPIServerClientConfig.CAFile = path.Join(certDir, "apiserver.crt")
-	kubeAPIServerClientConfig.CAData = nil
-	kubeAPIServerClientConfig.ServerName = ""
-
-	// wait for health
-	err = wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (done bool, err error) {
-		select {
-		case err := <-errCh:
-			return false, err
-		default:
+		if setup.ModifyServerRunOptions != nil {
+			setup.ModifyServerRunOptions(opts)
 		}
 
-		healthzConfig := rest.CopyConfig(kubeAPIServerClientConfig)
-		healthzConfig.ContentType = ""
-		healthzConfig.AcceptContentTypes = ""
-		kubeClient, err := client.NewForConfig(healthzConfig)
+		completedOptions, err := opts.Complete()
 		if err != nil {
-			// this happens because we race the API server start
-			t.Log(err)
-			return false, nil
+			t.Fatal(err)
 		}
 
-		healthStatus := 0
-		kubeClient.Discovery().RESTClient().Get().AbsPath("/healthz").Do(ctx).StatusCode(&healthStatus)
-		if healthStatus != http.StatusOK {
-			return false, nil
+		if errs := completedOptions.Validate(); len(errs) != 0 {
+			t.Fatalf("failed to validate ServerRunOptions: %v", utilerrors.NewAggregate(errs))
 		}
 
-		if _, err := kubeClient.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{}); err != nil {
-			return false, nil
+		kubeAPIServerConfig, _, _, err := app.CreateKubeAPIServerConfig(completedOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if setup.ModifyServerConfig != nil {
+			setup.ModifyServerConfig(kubeAPIServerConfig)
 		}
-		if _, err := kubeClient.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{}); err != nil {
-			return false, nil
+		kubeAPIServer, err := kubeAPIServerConfig.Complete().New(genericapiserver.NewEmptyDelegate())
+		if err != nil {
+			t.Fatal(err)
 		}
 
-		return true, nil
-	})
-	if err != nil {
-		t.Fatal(err)
-	}
+		errCh := make(chan error)
+		errChs = append(errChs, errCh)
+		go func() {
+			defer close(errCh)
+			if err := kubeAPIServer.GenericAPIServer.PrepareRun().Run(ctx.Done()); err != nil {
+				errCh <- err
+			}
+		}()
 
-	kubeAPIServerClient, err := client.NewForConfig(kubeAPIServerClientConfig)
-	if err != nil {
-		t.Fatal(err)
+		// Adjust the loopback config for external use (external server name and CA)
+		kubeAPIServerClientConfig := rest.CopyConfig(kubeAPIServerConfig.GenericConfig.LoopbackClientConfig)
+		kubeAPIServerClientConfig.CAFile = path.Join(serverCertDir, "apiserver.crt")
+		kubeAPIServerClientConfig.CAData = nil
+		kubeAPIServerClientConfig.ServerName = ""
+
+		// wait for health
+		err = wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (done bool, err error) {
+			select {
+			case err := <-errCh:
+				return false, err
+			default:
+			}
+
+			healthzConfig := rest.CopyConfig(kubeAPIServerClientConfig)
+			healthzConfig.ContentType = ""
+			healthzConfig.AcceptContentTypes = ""
+			kubeClient, err := client.NewForConfig(healthzConfig)
+			if err != nil {
+				// this happens because we race the API server start
+				t.Log(err)
+				return false, nil
+			}
+
+			healthStatus := 0
+			kubeClient.Discovery().RESTClient().Get().AbsPath("/healthz").Do(ctx).StatusCode(&healthStatus)
+			if healthStatus != http.StatusOK {
+				return false, nil
+			}
+
+			if _, err := kubeClient.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{}); err != nil {
+				return false, nil
+			}
+			if _, err := kubeClient.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{}); err != nil {
+				return false, nil
+			}
+
+			return true, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		kubeAPIServerClient, err := client.NewForConfig(kubeAPIServerClientConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clients = append(clients, kubeAPIServerClient)
+		configs = append(configs, kubeAPIServerClientConfig)
 	}
 
-	return kubeAPIServerClient, kubeAPIServerClientConfig, tearDownFn
+	return clients, configs, tearDownFn
 }
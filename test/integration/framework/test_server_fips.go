@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"k8s.io/kubernetes/cmd/kube-apiserver/app/options"
+	"k8s.io/kubernetes/test/utils"
+)
+
+// fipsCipherSuites is the FIPS 140-2 approved subset of the cipher suites
+// SecureServingOptions would otherwise default to.
+var fipsCipherSuites = []string{
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+}
+
+// applyFIPSOnly restricts opts to the FIPS-approved TLS subset when fipsOnly
+// is set, and fails fast rather than silently running a non-FIPS test server
+// if this binary wasn't built with the boringcrypto tag.
+func applyFIPSOnly(t testing.TB, opts *options.ServerRunOptions, fipsOnly bool) {
+	if !fipsOnly {
+		return
+	}
+	if !fipsOnlyBuild {
+		t.Fatalf("setup.FIPSOnly is set, but this test binary wasn't built with the boringcrypto FIPS tag; rebuild with GOEXPERIMENT=boringcrypto (or -tags=boringcrypto) to run the FIPS integration matrix")
+	}
+
+	opts.GenericControlPlane.SecureServing.CipherSuites = fipsCipherSuites
+	opts.GenericControlPlane.SecureServing.MinTLSVersion = "VersionTLS12"
+}
+
+// newCATestKey generates the private key used for the proxy/client test CAs.
+// The existing hard-coded ecdsaPrivateKey service-account key is P-256, which
+// is already FIPS-approved, but utils.NewPrivateKey (used for the two CAs
+// created fresh per test run) produces whatever curve test/utils currently
+// defaults to; under FIPS that's not guaranteed, so generate an RSA-2048 key
+// instead, which is unambiguously FIPS-approved regardless of what
+// test/utils picks.
+func newCATestKey(fipsOnly bool) (crypto.Signer, error) {
+	if !fipsOnly {
+		return utils.NewPrivateKey()
+	}
+	return rsa.GenerateKey(rand.Reader, 2048)
+}
@@ -0,0 +1,28 @@
+//go:build boringcrypto
+// +build boringcrypto
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+// Importing crypto/tls/fipsonly for its side effect restricts the process's
+// TLS stack to FIPS-approved algorithms for the lifetime of the binary, which
+// is what lets setup.FIPSOnly assert the test server it starts is actually
+// FIPS-constrained rather than merely configured to look that way.
+import _ "crypto/tls/fipsonly"
+
+const fipsOnlyBuild = true
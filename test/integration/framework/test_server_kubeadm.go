@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kubernetes/cmd/kube-apiserver/app/options"
+	netutils "k8s.io/utils/net"
+)
+
+// KubeadmClusterConfiguration mirrors the subset of kubeadm's
+// ClusterConfiguration (kubeadm.k8s.io/v1beta3) that StartTestServer knows how
+// to translate into ServerRunOptions. It exists here, rather than importing
+// the real kubeadm API types, because this tree doesn't vendor
+// k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm; the field names and nesting
+// intentionally match the upstream YAML so a bootstrapper's reported
+// ClusterConfiguration can be pasted in with minimal translation.
+type KubeadmClusterConfiguration struct {
+	APIServer  KubeadmAPIServer
+	Etcd       KubeadmEtcd
+	Networking KubeadmNetworking
+	// FeatureGates mirrors the top-level featureGates map kubeadm accepts in
+	// ClusterConfiguration (as opposed to apiServer.extraArgs["feature-gates"]).
+	FeatureGates map[string]bool
+}
+
+// KubeadmAPIServer mirrors ClusterConfiguration.APIServer.
+type KubeadmAPIServer struct {
+	ExtraArgs    map[string]string
+	CertSANs     []string
+	ExtraVolumes []KubeadmHostPathMount
+}
+
+// KubeadmHostPathMount mirrors kubeadm's HostPathMount, used for
+// apiServer.extraVolumes entries such as an admission control config file or
+// an encryption-at-rest config file.
+type KubeadmHostPathMount struct {
+	Name      string
+	HostPath  string
+	MountPath string
+	ReadOnly  bool
+}
+
+// KubeadmEtcd mirrors ClusterConfiguration.Etcd; exactly one of Local or
+// External is expected to be set, matching the real type's semantics.
+type KubeadmEtcd struct {
+	Local    *KubeadmLocalEtcd
+	External *KubeadmExternalEtcd
+}
+
+// KubeadmLocalEtcd mirrors ClusterConfiguration.Etcd.Local.
+type KubeadmLocalEtcd struct {
+	DataDir string
+}
+
+// KubeadmExternalEtcd mirrors ClusterConfiguration.Etcd.External.
+type KubeadmExternalEtcd struct {
+	Endpoints []string
+}
+
+// KubeadmNetworking mirrors ClusterConfiguration.Networking.
+type KubeadmNetworking struct {
+	ServiceSubnet string
+}
+
+// applyKubeadmClusterConfiguration translates cfg into opts, reproducing the
+// handful of ClusterConfiguration knobs that matter to an in-process test
+// apiserver. It runs before setup.ModifyServerRunOptions, so tests can still
+// layer ad-hoc overrides on top of a pasted-in bootstrapper config.
+func applyKubeadmClusterConfiguration(t testing.TB, opts *options.ServerRunOptions, cfg *KubeadmClusterConfiguration) {
+	if cfg == nil {
+		return
+	}
+
+	if cfg.Networking.ServiceSubnet != "" {
+		opts.ServiceClusterIPRanges = cfg.Networking.ServiceSubnet
+	}
+
+	if cfg.Etcd.External != nil && len(cfg.Etcd.External.Endpoints) > 0 {
+		opts.GenericControlPlane.Etcd.StorageConfig.Transport.ServerList = cfg.Etcd.External.Endpoints
+	}
+	if cfg.Etcd.Local != nil && cfg.Etcd.Local.DataDir != "" {
+		// This harness always talks to the shared etcd instance started for the
+		// test run (GetEtcdURL()) rather than spawning its own embedded etcd, so
+		// there's no data directory to point anywhere; accept the field for
+		// fidelity with a pasted-in config and note that it's a no-op here.
+		t.Logf("kubeadm ClusterConfiguration etcd.local.dataDir %q ignored: StartTestServer always uses the shared test etcd instance", cfg.Etcd.Local.DataDir)
+	}
+
+	for _, san := range cfg.APIServer.CertSANs {
+		if ip := netutils.ParseIPSloppy(san); ip != nil {
+			opts.GenericControlPlane.SecureServing.AlternateIPs = append(opts.GenericControlPlane.SecureServing.AlternateIPs, ip)
+		} else {
+			opts.GenericControlPlane.SecureServing.AlternateDNS = append(opts.GenericControlPlane.SecureServing.AlternateDNS, san)
+		}
+	}
+
+	for _, vol := range cfg.APIServer.ExtraVolumes {
+		// There's no container here to bind-mount into, so extraVolumes are only
+		// meaningful insofar as the corresponding extraArgs value (e.g.
+		// --admission-control-config-file or --encryption-provider-config) points
+		// at vol.HostPath directly; fail fast if that path doesn't actually exist
+		// rather than letting the apiserver produce a confusing startup error.
+		if _, err := os.Stat(vol.HostPath); err != nil {
+			t.Fatalf("apiServer.extraVolumes[%s].hostPath %q not found: %v", vol.Name, vol.HostPath, err)
+		}
+	}
+
+	// extraArgs and featureGates both map onto flags ServerRunOptions already
+	// knows how to parse, the same way kubeadm itself renders them into the
+	// static pod manifest's --flag=value arguments. Rather than re-deriving a
+	// typed field per possible flag, apply them by name through the merged
+	// FlagSet the options themselves expose.
+	fs := pflag.NewFlagSet("kubeadm-cluster-configuration", pflag.ContinueOnError)
+	for _, nfs := range opts.Flags().FlagSets {
+		fs.AddFlagSet(nfs)
+	}
+
+	if len(cfg.FeatureGates) > 0 {
+		pairs := make([]string, 0, len(cfg.FeatureGates))
+		for name, enabled := range cfg.FeatureGates {
+			pairs = append(pairs, fmt.Sprintf("%s=%t", name, enabled))
+		}
+		sort.Strings(pairs)
+		if err := fs.Set("feature-gates", strings.Join(pairs, ",")); err != nil {
+			t.Fatalf("invalid featureGates in kubeadm ClusterConfiguration: %v", err)
+		}
+	}
+
+	names := make([]string, 0, len(cfg.APIServer.ExtraArgs))
+	for name := range cfg.APIServer.ExtraArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := fs.Set(name, cfg.APIServer.ExtraArgs[name]); err != nil {
+			t.Fatalf("invalid apiServer.extraArgs[%s]=%q in kubeadm ClusterConfiguration: %v", name, cfg.APIServer.ExtraArgs[name], err)
+		}
+	}
+}
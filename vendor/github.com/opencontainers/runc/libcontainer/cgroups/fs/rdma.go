@@ -1,6 +1,12 @@
 package fs
 
 import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/runc/libcontainer/cgroups/fscommon"
 	"github.com/opencontainers/runc/libcontainer/configs"
@@ -25,6 +31,110 @@ func (s *RdmaGroup) Set(path string, r *configs.Resources) error {
 	return fscommon.RdmaSet(path, r)
 }
 
+// rdmaDeviceEntry is the per-device accounting recorded in an rdma.current
+// or rdma.max file, e.g. "mlx5_0 hca_handle=2 hca_object=23".
+type rdmaDeviceEntry struct {
+	device     string
+	hcaHandles uint32
+	hcaObjects uint32
+}
+
 func (s *RdmaGroup) GetStats(path string, stats *cgroups.Stats) error {
-	return fscommon.RdmaGetStats(path, stats)
+	current, err := rdmaParseFile(filepath.Join(path, "rdma.current"))
+	if err != nil {
+		return err
+	}
+	limit, err := rdmaParseFile(filepath.Join(path, "rdma.max"))
+	if err != nil {
+		return err
+	}
+
+	currentByDevice := make(map[string]rdmaDeviceEntry, len(current))
+	for _, entry := range current {
+		currentByDevice[entry.device] = entry
+	}
+	limitByDevice := make(map[string]rdmaDeviceEntry, len(limit))
+	for _, entry := range limit {
+		limitByDevice[entry.device] = entry
+	}
+
+	devices := make(map[string]struct{}, len(currentByDevice)+len(limitByDevice))
+	for device := range currentByDevice {
+		devices[device] = struct{}{}
+	}
+	for device := range limitByDevice {
+		devices[device] = struct{}{}
+	}
+
+	rdmaStats := make(map[string]cgroups.RdmaHCAStats, len(devices))
+	for device := range devices {
+		cur := currentByDevice[device]
+		lim := limitByDevice[device]
+		rdmaStats[device] = cgroups.RdmaHCAStats{
+			HcaHandles: cgroups.RdmaEntry{Current: cur.hcaHandles, Limit: lim.hcaHandles},
+			HcaObjects: cgroups.RdmaEntry{Current: cur.hcaObjects, Limit: lim.hcaObjects},
+		}
+	}
+
+	stats.RdmaStats = rdmaStats
+	return nil
+}
+
+// rdmaParseFile parses an rdma.current/rdma.max style file. Missing files
+// (the kernel doesn't have the rdma controller compiled in, or no resources
+// were ever set) are not an error: they just mean there's nothing to report.
+func rdmaParseFile(path string) ([]rdmaDeviceEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []rdmaDeviceEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		entry := rdmaDeviceEntry{device: fields[0]}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			val, err := rdmaParseValue(kv[1])
+			if err != nil {
+				continue
+			}
+			switch kv[0] {
+			case "hca_handle":
+				entry.hcaHandles = val
+			case "hca_object":
+				entry.hcaObjects = val
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// rdmaParseValue parses one side of a "key=value" rdma.current/rdma.max
+// field. The kernel reports "max" for an unset/unlimited resource, which we
+// surface as 0 since there's nothing meaningful to compare it against.
+func rdmaParseValue(s string) (uint32, error) {
+	if s == "max" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(v), nil
 }